@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kream404/spoof/services/pgp"
+)
+
+var (
+	decryptKeys       []string
+	decryptVerify     []string
+	decryptPassphrase string
+	decryptOutput     string
+)
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Decrypt a PGP-encrypted output file and check its signature",
+	Args:  cobra.ExactArgs(1),
+	// a wrong key or bad signature is not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passphrase := decryptPassphrase
+		if passphrase == "" {
+			passphrase = os.Getenv("SPOOF_PGP_PASSPHRASE")
+		}
+
+		out := decryptOutput
+		if out == "" {
+			out = DecryptedPath(args[0])
+		}
+		res, err := DecryptFile(args[0], out, decryptKeys, decryptVerify, passphrase)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case res.Verified:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: good signature from %s\n", out, res.KeyID)
+		case res.Signed:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: signed by %s, not verified (pass its public key with --verify)\n", out, res.KeyID)
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "%s: not signed\n", out)
+		}
+		return nil
+	},
+}
+
+// DecryptedPath is path without its .gpg, .pgp or .asc extension.
+func DecryptedPath(path string) string {
+	for _, ext := range []string{".gpg", ".pgp", ".asc"} {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return path[:len(path)-len(ext)]
+		}
+	}
+	return path + ".dec"
+}
+
+// DecryptFile decrypts path to out with the private keys in keyPaths. A signature
+// by one of the public keys in verifyPaths is verified.
+func DecryptFile(path, out string, keyPaths, verifyPaths []string, passphrase string) (pgp.Result, error) {
+	if len(keyPaths) == 0 {
+		return pgp.Result{}, fmt.Errorf("pass the private key to decrypt with using --key")
+	}
+
+	keyring, err := readKeyFiles(append(append([]string(nil), keyPaths...), verifyPaths...))
+	if err != nil {
+		return pgp.Result{}, err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return pgp.Result{}, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(out)
+	if err != nil {
+		return pgp.Result{}, err
+	}
+	defer dst.Close()
+
+	res, err := pgp.Decrypt(dst, src, keyring, passphrase)
+	if err != nil {
+		dst.Close()
+		os.Remove(out)
+		return res, fmt.Errorf("decrypt %s: %w", path, err)
+	}
+	return res, dst.Close()
+}
+
+func readKeyFiles(paths []string) (pgp.KeyRing, error) {
+	var keyring pgp.KeyRing
+	for _, p := range paths {
+		keys, err := pgp.ReadKeys(p)
+		if err != nil {
+			return nil, err
+		}
+		keyring = append(keyring, keys...)
+	}
+	return keyring, nil
+}
+
+func init() {
+	decryptCmd.Flags().StringArrayVarP(&decryptKeys, "key", "k", nil, "private key file to decrypt with (ASCII-armored or binary)")
+	decryptCmd.Flags().StringArrayVar(&decryptVerify, "verify", nil, "public key file to verify the signature with")
+	decryptCmd.Flags().StringVar(&decryptPassphrase, "passphrase", "", "passphrase for the private key (default $SPOOF_PGP_PASSPHRASE)")
+	decryptCmd.Flags().StringVarP(&decryptOutput, "output", "o", "", "where to write the decrypted file (default: the file without .gpg/.asc)")
+	rootCmd.AddCommand(decryptCmd)
+}
@@ -0,0 +1,89 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kream404/spoof/cmd"
+	"github.com/kream404/spoof/models"
+	csvgen "github.com/kream404/spoof/services/csv"
+)
+
+// writeKey writes e's public key, or its private key, to dir/name.
+func writeKey(t *testing.T, e *openpgp.Entity, dir, name string, private bool) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	blockType := "PGP PUBLIC KEY BLOCK"
+	if private {
+		blockType = "PGP PRIVATE KEY BLOCK"
+	}
+	w, err := armor.Encode(f, blockType, nil)
+	assert.NoError(t, err)
+	if private {
+		assert.NoError(t, e.SerializePrivateWithoutSigning(w, nil))
+	} else {
+		assert.NoError(t, e.Serialize(w))
+	}
+	assert.NoError(t, w.Close())
+	return path
+}
+
+func TestDecryptFile_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	partner, err := openpgp.NewEntity("Partner", "", "partner@example.com", nil)
+	assert.NoError(t, err)
+	sender, err := openpgp.NewEntity("Sender", "", "sender@example.com", nil)
+	assert.NoError(t, err)
+
+	partnerPub := writeKey(t, partner, dir, "partner.asc", false)
+	partnerKey := writeKey(t, partner, dir, "partner.key", true)
+	senderPub := writeKey(t, sender, dir, "sender.asc", false)
+	assert.NoError(t, sender.EncryptPrivateKeys([]byte("s3cret"), nil))
+	senderKey := writeKey(t, sender, dir, "sender.key", true)
+
+	entity := models.Entity{
+		Config: models.Config{FileName: "payments.csv", Delimiter: ",", IncludeHeaders: true, RowCount: 2},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+		Postprocess: models.Postprocess{
+			Enabled: true,
+			Encrypt: &models.EncryptOptions{Recipients: []string{partnerPub}, Armor: true, SignKey: senderKey, Passphrase: "s3cret"},
+		},
+	}
+	defer os.RemoveAll("output")
+	assert.NoError(t, csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true))
+
+	out := filepath.Join(dir, "payments.csv")
+	res, err := cmd.DecryptFile("output/payments.csv.asc", out, []string{partnerKey}, []string{senderPub}, "")
+	assert.NoError(t, err)
+	assert.True(t, res.Signed)
+	assert.True(t, res.Verified)
+
+	plain, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "id\n2\n3\n", string(plain))
+
+	// without the sender's public key the signature can't be checked
+	res, err = cmd.DecryptFile("output/payments.csv.asc", out, []string{partnerKey}, nil, "")
+	assert.NoError(t, err)
+	assert.True(t, res.Signed)
+	assert.False(t, res.Verified)
+
+	// the sender can't read what was encrypted for the partner
+	_, err = cmd.DecryptFile("output/payments.csv.asc", out, []string{senderKey}, nil, "s3cret")
+	assert.Error(t, err)
+}
+
+func TestDecryptedPath(t *testing.T) {
+	assert.Equal(t, "output/payments.csv", cmd.DecryptedPath("output/payments.csv.gpg"))
+	assert.Equal(t, "output/payments.csv", cmd.DecryptedPath("output/payments.csv.asc"))
+	assert.Equal(t, "payments.bin.dec", cmd.DecryptedPath("payments.bin"))
+}
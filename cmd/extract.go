@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 	"github.com/kream404/spoof/services/csv"
 	log "github.com/kream404/spoof/services/logger"
 
@@ -18,7 +19,7 @@ var extractCmd = &cobra.Command{
 	Use:  "extract",
 	Long: `extract a new config file`,
 	Run: func(cmd *cobra.Command, args []string) {
-		config, err := ExtractConfigFile(args[0])
+		config, err := ExtractConfigFile(args[0], extractFixed)
 		if err != nil {
 			log.Error("Failed to extract config	", "error", err.Error())
 			os.Exit(1)
@@ -33,7 +34,11 @@ var extractCmd = &cobra.Command{
 	},
 }
 
-func ExtractConfigFile(path string) (*models.FileConfig, error) {
+func ExtractConfigFile(path string, fixed bool) (*models.FileConfig, error) {
+	if fixed {
+		return extractFixedWidth(path)
+	}
+
 	records, filename, delimiter, header, footer, err := csv.ReadCSV(path)
 	if err != nil {
 		log.Error("Failed to read csv	", "path", path)
@@ -42,7 +47,7 @@ func ExtractConfigFile(path string) (*models.FileConfig, error) {
 
 	fields, types, _ := csv.MapFields(records)
 	config := models.Config{
-		FileName:       filepath.Base(filename),
+		FileName:       filepath.Base(compress.TrimExt(filename)),
 		Delimiter:      string(delimiter),
 		RowCount:       len(records) - 1,
 		IncludeHeaders: true,
@@ -66,6 +71,35 @@ func ExtractConfigFile(path string) (*models.FileConfig, error) {
 	return fileConfig, nil
 }
 
+// extractFixedWidth infers a fixed format config, with column widths, from a fixed-width file.
+func extractFixedWidth(path string) (*models.FileConfig, error) {
+	lines, filename, header, footer, err := csv.ReadFixedWidth(path)
+	if err != nil {
+		log.Error("Failed to read fixed-width file	", "path", path)
+		return nil, err
+	}
+
+	fields, types, err := csv.MapFixedFields(lines)
+	if err != nil {
+		return nil, err
+	}
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: filepath.Base(compress.TrimExt(filename)),
+			Format:   "fixed",
+			RowCount: len(lines),
+			Header:   header,
+			Footer:   footer,
+		},
+		Fields: fields,
+	}
+
+	log.Info("Extracted fixed-width config")
+	log.Debug("Summary	", "field_types", fmt.Sprint(types), "count", fmt.Sprint(len(entity.Fields)))
+
+	return &models.FileConfig{Files: []models.Entity{entity}}, nil
+}
+
 func WriteConfigToFile(config *models.FileConfig, path string) error {
 
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/spf13/cobra"
+)
+
+var fakersCmd = &cobra.Command{
+	Use:   "fakers",
+	Short: "List and describe the available fakers",
+}
+
+var fakersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every faker type",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loadPlugins()
+		ListFakers(os.Stdout)
+		return nil
+	},
+}
+
+var fakersDescribeCmd = &cobra.Command{
+	Use:   "describe <type>",
+	Short: "Show a faker's attributes, functions and examples",
+	Args:  cobra.ExactArgs(1),
+	// an unknown type is not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loadPlugins()
+		return DescribeFaker(os.Stdout, args[0])
+	},
+}
+
+func loadPlugins() {
+	if _, err := fakers.LoadPlugins(fakers.PluginDir(), pluginTimeout); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load plugins:", err)
+	}
+}
+
+func ListFakers(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, info := range fakers.ListFakers() {
+		fmt.Fprintf(tw, "%s\t%s\n", info.Name, info.Description)
+	}
+	tw.Flush()
+}
+
+func DescribeFaker(w io.Writer, name string) error {
+	info, ok := fakers.GetFakerInfo(strings.ToLower(name))
+	if !ok {
+		return fmt.Errorf("unknown faker %q; run `spoof fakers list` to see the available types", name)
+	}
+
+	fmt.Fprintf(w, "%s\n", info.Name)
+	if info.Description != "" {
+		fmt.Fprintf(w, "  %s\n", info.Description)
+	}
+
+	fmt.Fprintln(w, "\nAttributes:")
+	switch {
+	case info.AnyAttributes:
+		fmt.Fprintln(w, "  any (the whole field config is passed to the faker)")
+	case len(info.Attributes) == 0:
+		fmt.Fprintln(w, "  none")
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  NAME\tTYPE\tDEFAULT\tDESCRIPTION")
+		for _, a := range info.Attributes {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", a.Name, a.Type, a.Default, a.Description)
+		}
+		tw.Flush()
+	}
+
+	if len(info.Functions) > 0 {
+		fmt.Fprintf(w, "\nFunctions:\n  %s\n", strings.Join(info.Functions, ", "))
+	}
+
+	if len(info.Examples) > 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for _, e := range info.Examples {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+	return nil
+}
+
+func init() {
+	fakersCmd.AddCommand(fakersListCmd, fakersDescribeCmd)
+	rootCmd.AddCommand(fakersCmd)
+}
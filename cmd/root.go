@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/go-ini/ini"
+	"github.com/kream404/spoof/fakers"
 	"github.com/kream404/spoof/models"
 	"github.com/kream404/spoof/services/csv"
 	json_service "github.com/kream404/spoof/services/json"
@@ -22,18 +23,22 @@ import (
 )
 
 var (
-	configPath   string
-	cfg          *models.FileConfig
-	scaffold     bool
-	scaffoldName string
-	profile      string
-	showVersion  bool
-	dryRun       bool
-	force        bool
-	verbose      bool
-	generate     bool
-	extractPath  string
-	injectVars   []string
+	configPath    string
+	cfg           *models.FileConfig
+	scaffold      bool
+	scaffoldName  string
+	scaffoldAttrs []string
+	profile       string
+	showVersion   bool
+	dryRun        bool
+	force         bool
+	verbose       bool
+	generate      bool
+	extractPath   string
+	extractFixed  bool
+	outputDir     string
+	injectVars    []string
+	pluginTimeout time.Duration
 )
 
 // Root command
@@ -52,8 +57,12 @@ var rootCmd = &cobra.Command{
 			log.Init(slog.LevelInfo)
 		}
 
-		if scaffold && scaffoldName != "" {
-			runScaffold()
+		if _, err := fakers.LoadPlugins(fakers.PluginDir(), pluginTimeout); err != nil {
+			log.Warn("Failed to load plugins", "error", err.Error())
+		}
+
+		if scaffold {
+			return runScaffold()
 		}
 
 		if extractPath != "" {
@@ -75,8 +84,9 @@ var rootCmd = &cobra.Command{
 			return errors.New("no configuration loaded")
 		}
 
-		ProcessFiles(force, dryRun)
-		return nil
+		// a generation failure is not a usage error
+		cmd.SilenceUsage = true
+		return ProcessFiles(force, dryRun)
 	},
 }
 
@@ -127,20 +137,25 @@ func runGenerate(cmd *cobra.Command) error {
 	return nil
 }
 
-func ProcessFiles(force bool, dryRun bool) {
-	csv.ProcessFiles(*cfg, force, dryRun)
+func ProcessFiles(force bool, dryRun bool) error {
+	return csv.ProcessFilesTo(*cfg, outputDir, force, dryRun)
 }
 
-func runScaffold() {
-	fmt.Println("generating faker..")
-	fmt.Println("scaffold_name:", scaffoldName)
+func runScaffold() error {
+	if scaffoldName == "" {
+		return errors.New("--scaffold requires --scaffold_name")
+	}
+	log.Info("Generating faker", "name", scaffoldName, "attributes", scaffoldAttrs)
 
-	fakerConfig := FakerConfig{
-		Name:     scaffoldName,
-		DataType: scaffoldName,
-		Format:   "",
+	fakerConfig, err := NewFakerConfig(scaffoldName, scaffoldAttrs)
+	if err != nil {
+		return err
+	}
+	written, err := GenerateFaker(fakerConfig)
+	for _, path := range written {
+		log.Info("Wrote", "file", path)
 	}
-	GenerateFaker(fakerConfig)
+	return err
 }
 
 func loadConfig() error {
@@ -267,9 +282,9 @@ func applyProfile(fc *models.FileConfig, name string) error {
 		return errors.New("profile not found")
 	}
 	if cacheProfile.Password == "" {
-		fmt.Print("enter db password: ")
+		fmt.Fprint(os.Stderr, "enter db password: ")
 		pw, _ := term.ReadPassword(int(os.Stdin.Fd()))
-		fmt.Println()
+		fmt.Fprintln(os.Stderr)
 		cacheProfile.Password = string(pw)
 	}
 
@@ -306,10 +321,14 @@ func init() {
 	rootCmd.Flags().BoolVarP(&generate, "generate", "g", false, "generate a new config file")
 	rootCmd.Flags().StringArrayVarP(&injectVars, "inject", "i", []string{}, "variables to inject (key=value)")
 	rootCmd.Flags().StringVarP(&extractPath, "extract", "e", "", "extract config file from csv")
+	rootCmd.Flags().BoolVar(&extractFixed, "fixed", false, "extract from a fixed-width file instead of csv")
 	rootCmd.Flags().StringVarP(&configPath, "config", "c", "", "path to config file")
+	rootCmd.Flags().StringVarP(&outputDir, "output", "o", "output", "directory to write files to, or - for stdout")
 	rootCmd.Flags().StringVarP(&profile, "profile", "p", "", "db connection profile")
 	rootCmd.Flags().BoolVarP(&scaffold, "scaffold", "s", false, "generate new faker scaffold")
 	rootCmd.Flags().StringVarP(&scaffoldName, "scaffold_name", "n", "", "name of new faker")
+	rootCmd.Flags().StringSliceVar(&scaffoldAttrs, "scaffold_attrs", nil, "field attributes the new faker reads, e.g. length,min,max (format is always included)")
+	rootCmd.Flags().DurationVar(&pluginTimeout, "plugin_timeout", fakers.DefaultPluginTimeout, "timeout for each plugin faker call")
 }
 
 func Execute() {
@@ -1,87 +1,312 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"go/format"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 )
 
+const modulePath = "github.com/kream404/spoof"
+
 type FakerConfig struct {
-	Name       string // Faker name (e.g., "UUIDFaker", "EmailFaker")
-	DataType   string // Underlying type (e.g., "uuid.UUID", "string")
-	Format     string // Format information
+	Name       string      // Faker name as given on the command line (e.g. "LedgerRef")
+	TypeName   string      // Go type prefix (e.g. "LedgerRef" -> LedgerRefFaker)
+	Key        string      // registered field type (e.g. "ledgerref")
+	Attributes []Attribute // field attributes the faker consumes, format first
+}
+
+// Attribute is a models.Field attribute a scaffolded faker reads.
+type Attribute struct {
+	Name   string // json name in the config
+	Type   string // attribute type in the faker's FakerInfo
+	Param  string // constructor parameter
+	GoType string
+	Field  string // expression reading it from field
+	Float  bool   // min/max bounds, converted with Bound.Float
+}
+
+// scaffoldAttributes are the attributes --scaffold_attrs accepts.
+var scaffoldAttributes = map[string]Attribute{
+	"format":      {Name: "format", Type: "string", Param: "format", GoType: "string", Field: "field.Format"},
+	"length":      {Name: "length", Type: "int", Param: "length", GoType: "int", Field: "field.Length"},
+	"min":         {Name: "min", Type: "number", Param: "min", GoType: "float64", Field: "field.Min", Float: true},
+	"max":         {Name: "max", Type: "number", Param: "max", GoType: "float64", Field: "field.Max", Float: true},
+	"values":      {Name: "values", Type: "list", Param: "values", GoType: "string", Field: "field.Values"},
+	"scheme":      {Name: "scheme", Type: "string", Param: "scheme", GoType: "string", Field: "field.Scheme"},
+	"regex":       {Name: "regex", Type: "string", Param: "regex", GoType: "string", Field: "field.Regex"},
+	"timezone":    {Name: "timezone", Type: "string", Param: "timezone", GoType: "string", Field: "field.Timezone"},
+	"function":    {Name: "function", Type: "string", Param: "function", GoType: "string", Field: "field.Function"},
+	"interval":    {Name: "interval", Type: "int", Param: "interval", GoType: "int64", Field: "field.Interval"},
+	"probability": {Name: "probability", Type: "number", Param: "probability", GoType: "*float64", Field: "field.Probability"},
+	"start":       {Name: "start", Type: "int", Param: "start", GoType: "*int", Field: "field.Start"},
 }
 
+var fakerNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
 const fakerTemplate = `package fakers
 
 import (
 	"fmt"
-	"github.com/kream404/spoof/models"
 	"math/rand"
+
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
 )
 
-type {{.Name}}Faker struct {
+type {{.TypeName}}Faker struct {
 	datatype models.Type
-	format   string
-	rng 		 *rand.Rand
+{{- range .Attributes}}
+	{{.Param}} {{.GoType}}
+{{- end}}
+	rng *rand.Rand
 }
 
-func (f *{{.Name}}Faker) Generate() ({{.DataType}}, error) {
-	//TODO: Implement generation logic
-	var value {{.DataType}}
-	//TODO: Add proper generation logic here
-	fmt.Println("spoofed {{.Name}}:", value)
-	return value, nil
+func (f *{{.TypeName}}Faker) Generate() (any, error) {
+	// TODO: replace with the real generation logic
+	n := rand.Intn(1000000)
+	if f.rng != nil {
+		n = f.rng.Intn(1000000)
+	}
+	return fmt.Sprintf("{{.Key | toUpper}}-%06d", n), nil
 }
 
-func (f *{{.Name}}Faker) GetType() models.Type {
-	return f.datatype
-}
+func (f *{{.TypeName}}Faker) GetType() models.Type { return f.datatype }
+func (f *{{.TypeName}}Faker) GetFormat() string    { return f.format }
 
-func (f *{{.Name}}Faker) GetFormat() string {
-	return f.format
-}
-
-func New{{.Name}}Faker(format string, rng *rand.Rand) *{{.Name}}Faker {
-	return &{{.Name}}Faker{
-		datatype: models.Type("{{.DataType}}"),
-		format:   "format",
+func New{{.TypeName}}Faker({{range .Attributes}}{{.Param}} {{.GoType}}, {{end}}rng *rand.Rand) (*{{.TypeName}}Faker, error) {
+	return &{{.TypeName}}Faker{
+		datatype: models.Type("{{.TypeName}}"),
+{{- range .Attributes}}
+		{{.Param}}: {{.Param}},
+{{- end}}
 		rng: rng,
-	}
+	}, nil
 }
 
 func init() {
-	RegisterFaker("{{.Name | toLower}}", &{{.Name}}Faker{
-		datatype: models.Type("{{.Name}}"),
-		format:   "",
+	RegisterFaker("{{.Key}}", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
+{{- range .Attributes}}{{if .Float}}
+		{{.Param}}, err := {{.Field}}.Float()
+		if err != nil {
+			return nil, fmt.Errorf("invalid {{$.Key}} config: {{.Name}} %w", err)
+		}
+{{- end}}{{end}}
+		return New{{.TypeName}}Faker({{range .Attributes}}{{if .Float}}{{.Param}}{{else}}{{.Field}}{{end}}, {{end}}rng)
 	})
+
+	DescribeFaker("{{.Key}}", FakerInfo{
+		Description: "TODO: describe what the {{.Key}} faker generates.",
+		Attributes: []Attribute{
+{{- range .Attributes}}
+			{Name: "{{.Name}}", Type: "{{.Type}}", Description: "TODO"},
+{{- end}}
+		},
+		Examples: []string{` + "`" + `{ "name": "{{.Key}}", "type": "{{.Key}}" }` + "`" + `},
+	})
+}
+`
+
+const fakerTestTemplate = `package fakers_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test{{.TypeName}}Faker(t *testing.T) {
+	tests := []struct {
+		name  string
+		field models.Field
+	}{
+		{"defaults", models.Field{Name: "{{.Key}}", Type: "{{.Key}}"}},
+		// TODO: add a case for each attribute: {{range $i, $a := .Attributes}}{{if $i}}, {{end}}{{$a.Name}}{{end}}
+	}
+
+	factory, ok := fakers.GetFakerByName("{{.Key}}")
+	assert.True(t, ok)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			faker, err := factory(tt.field, rand.New(rand.NewSource(1)))
+			assert.NoError(t, err)
+
+			v, err := faker.Generate()
+			assert.NoError(t, err)
+			assert.NotEmpty(t, v)
+		})
+	}
 }
 `
 
-func GenerateFaker(config FakerConfig) error {
-	fileName := fmt.Sprintf("fakers/%s.go", config.Name)
-	fileName = strings.ToLower(fileName)
+const fakerDocTemplate = `### ` + "`{{.Key}}`" + `
+
+TODO: describe what the {{.Key}} faker generates.
+{{range .Attributes}}
+- ` + "`{{.Name}}`" + `: TODO
+{{- end}}
+
+` + "```json" + `
+{ "name": "{{.Key}}", "type": "{{.Key}}" }
+` + "```" + `
+
+---
+
+`
 
+// NewFakerConfig validates the faker name and attributes for scaffolding.
+func NewFakerConfig(name string, attrs []string) (FakerConfig, error) {
+	if !fakerNameRe.MatchString(name) {
+		return FakerConfig{}, fmt.Errorf("invalid faker name %q: use letters and digits, starting with a letter", name)
+	}
+
+	config := FakerConfig{
+		Name:       name,
+		TypeName:   strings.ToUpper(name[:1]) + name[1:],
+		Key:        strings.ToLower(name),
+		Attributes: []Attribute{scaffoldAttributes["format"]},
+	}
+
+	seen := map[string]bool{"format": true}
+	for _, a := range attrs {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "" || seen[a] {
+			continue
+		}
+		attr, ok := scaffoldAttributes[a]
+		if !ok {
+			return FakerConfig{}, fmt.Errorf("unsupported attribute %q (supported: %s)", a, strings.Join(supportedAttributes(), ", "))
+		}
+		seen[a] = true
+		config.Attributes = append(config.Attributes, attr)
+	}
+	return config, nil
+}
+
+func supportedAttributes() []string {
+	names := make([]string, 0, len(scaffoldAttributes))
+	for n := range scaffoldAttributes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RenderFaker returns the gofmt'd faker source and its test.
+func RenderFaker(config FakerConfig) (source []byte, test []byte, err error) {
+	if source, err = render("faker", fakerTemplate, config, true); err != nil {
+		return nil, nil, err
+	}
+	if test, err = render("faker_test", fakerTestTemplate, config, true); err != nil {
+		return nil, nil, err
+	}
+	return source, test, nil
+}
+
+func render(name, text string, config FakerConfig, gofmt bool) ([]byte, error) {
 	funcMap := template.FuncMap{
-			"toLower": strings.ToLower,
+		"toLower": strings.ToLower,
+		"toUpper": strings.ToUpper,
 	}
+	tmpl, err := template.New(name).Funcs(funcMap).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, config); err != nil {
+		return nil, err
+	}
+	if !gofmt {
+		return buf.Bytes(), nil
+	}
+	return format.Source(buf.Bytes())
+}
 
-	_, err := os.Stat(fileName)
-	if(err == nil){
-		return fmt.Errorf("file %s already exists", fileName)
+// moduleRoot returns dir if it is the root of the spoof module.
+func moduleRoot(dir string) (string, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil || !strings.Contains(string(raw), "module "+modulePath+"\n") {
+		return "", fmt.Errorf("scaffold must be run from the root of the %s module", modulePath)
 	}
+	if info, err := os.Stat(filepath.Join(dir, "fakers")); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("scaffold must be run from the root of the %s module: no fakers directory", modulePath)
+	}
+	return dir, nil
+}
 
-	f, err := os.Create(fileName)
-	if(err != nil){
-		return err
+// GenerateFaker writes fakers/<key>.go, fakers/<key>_test.go and a docs stub in docs/config.md.
+// Nothing is written if any of the faker files already exist.
+func GenerateFaker(config FakerConfig) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	root, err := moduleRoot(cwd)
+	if err != nil {
+		return nil, err
 	}
-	defer f.Close()
 
-	tmpl, err := template.New("faker").Funcs(funcMap).Parse(fakerTemplate)
-	if(err != nil){
+	source, test, err := RenderFaker(config)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := render("doc", fakerDocTemplate, config, false)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		filepath.Join(root, "fakers", config.Key+".go"):      source,
+		filepath.Join(root, "fakers", config.Key+"_test.go"): test,
+	}
+	for path := range files {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("file %s already exists", path)
+		}
+	}
+
+	var written []string
+	for path, content := range files {
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	docPath := filepath.Join(root, "docs", "config.md")
+	if err := insertDocStub(docPath, doc); err != nil {
+		return written, err
+	}
+	written = append(written, docPath)
+	sort.Strings(written)
+	return written, nil
+}
+
+// insertDocStub adds the faker's section at the end of the field types, before "## Functions".
+func insertDocStub(path string, doc []byte) error {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.WriteFile(path, doc, 0o644)
+	}
+	if err != nil {
 		return err
 	}
 
-	return tmpl.Execute(f, config)
+	text := string(raw)
+	if i := strings.Index(text, "\n## Functions"); i >= 0 {
+		text = text[:i+1] + string(doc) + text[i+1:]
+	} else {
+		text = strings.TrimRight(text, "\n") + "\n\n---\n\n" + string(doc)
+	}
+	return os.WriteFile(path, []byte(text), 0o644)
 }
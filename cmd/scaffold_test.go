@@ -0,0 +1,47 @@
+package cmd_test
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/kream404/spoof/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderFaker(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs []string
+	}{
+		{"Ledger", nil},
+		{"productCode", []string{"length", "min", "max", "values", "probability", "start", "format"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := cmd.NewFakerConfig(tt.name, tt.attrs)
+			assert.NoError(t, err)
+
+			source, test, err := cmd.RenderFaker(config)
+			assert.NoError(t, err)
+			for _, src := range [][]byte{source, test} {
+				_, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+				assert.NoError(t, err)
+			}
+			assert.Contains(t, string(source), `RegisterFaker("`+config.Key+`", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error)`)
+			// without metadata the faker would skip attribute validation
+			assert.Contains(t, string(source), `DescribeFaker("`+config.Key+`", FakerInfo{`)
+			for _, a := range config.Attributes {
+				assert.Contains(t, string(source), `{Name: "`+a.Name+`", Type: "`+a.Type+`"`)
+			}
+		})
+	}
+}
+
+func TestNewFakerConfig_Invalid(t *testing.T) {
+	_, err := cmd.NewFakerConfig("ledger-ref", nil)
+	assert.ErrorContains(t, err, "invalid faker name")
+
+	_, err = cmd.NewFakerConfig("Ledger", []string{"colour"})
+	assert.ErrorContains(t, err, `unsupported attribute "colour"`)
+}
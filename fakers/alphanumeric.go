@@ -90,4 +90,17 @@ func init() {
 		}
 		return faker, nil
 	})
+
+	DescribeFaker("alphanumeric", FakerInfo{
+		Description: "Random letters and digits of a fixed length, or strings matching a regex.",
+		Attributes: []Attribute{
+			{Name: "length", Type: "int", Description: "number of characters (required unless regex is set)"},
+			{Name: "format", Type: "string", Default: "mixed", Description: "upper | lower | mixed"},
+			{Name: "regex", Type: "string", Description: "generate strings matching this pattern instead"},
+		},
+		Examples: []string{
+			`{ "name": "reference", "type": "alphanumeric", "length": 12, "format": "upper" }`,
+			`{ "name": "sort_code", "type": "alphanumeric", "regex": "[0-9]{2}-[0-9]{2}-[0-9]{2}" }`,
+		},
+	})
 }
@@ -0,0 +1,76 @@
+package fakers
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
+)
+
+type BooleanFaker struct {
+	datatype    models.Type
+	format      string // "<true>/<false>" output tokens, e.g. "Y/N"; default "true/false"
+	probability float64
+	trueToken   string
+	falseToken  string
+	rng         *rand.Rand
+}
+
+func (f *BooleanFaker) Generate() (any, error) {
+	if f.rng.Float64() < f.probability {
+		return models.BoolText(true, f.trueToken), nil
+	}
+	return models.BoolText(false, f.falseToken), nil
+}
+
+func (f *BooleanFaker) GetType() models.Type { return f.datatype }
+func (f *BooleanFaker) GetFormat() string    { return f.format }
+
+func NewBooleanFaker(format string, probability *float64, rng *rand.Rand) (*BooleanFaker, error) {
+	p := 0.5
+	if probability != nil {
+		p = *probability
+	}
+	if p < 0 || p > 1 {
+		return nil, fmt.Errorf("boolean: probability must be between 0 and 1 (got %v)", p)
+	}
+
+	trueToken, falseToken := "true", "false"
+	if strings.TrimSpace(format) != "" {
+		parts := strings.SplitN(format, "/", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			return nil, fmt.Errorf("boolean: invalid format %q (expected \"<true>/<false>\", e.g. \"Y/N\")", format)
+		}
+		trueToken, falseToken = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+
+	return &BooleanFaker{
+		datatype:    models.Type("Boolean"),
+		format:      format,
+		probability: p,
+		trueToken:   trueToken,
+		falseToken:  falseToken,
+		rng:         rng,
+	}, nil
+}
+
+func init() {
+	RegisterFaker("boolean", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
+		faker, err := NewBooleanFaker(field.Format, field.Probability, rng)
+		if err != nil {
+			return nil, err
+		}
+		return faker, nil
+	})
+
+	DescribeFaker("boolean", FakerInfo{
+		Description: "A boolean value with a configurable chance of true.",
+		Attributes: []Attribute{
+			{Name: "probability", Type: "number", Default: "0.5", Description: "chance (0..1) of true"},
+			{Name: "format", Type: "string", Default: "true/false", Description: "output tokens as <true>/<false>, e.g. Y/N or 1/0"},
+		},
+		Examples: []string{`{ "name": "is_active", "type": "boolean", "probability": 0.8, "format": "Y/N" }`},
+	})
+}
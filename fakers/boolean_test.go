@@ -0,0 +1,59 @@
+package fakers_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBooleanFaker_Probability(t *testing.T) {
+	for _, p := range []float64{0, 0.2, 0.8, 1} {
+		probability := p
+		faker, err := fakers.NewBooleanFaker("", &probability, rand.New(rand.NewSource(42)))
+		assert.NoError(t, err)
+
+		trues := 0
+		const n = 10000
+		for i := 0; i < n; i++ {
+			v, err := faker.Generate()
+			assert.NoError(t, err)
+			if v.(models.Value).Bool {
+				trues++
+			}
+		}
+		assert.InDelta(t, p, float64(trues)/n, 0.02, "true rate for probability %v", p)
+	}
+}
+
+func TestBooleanFaker_Format(t *testing.T) {
+	faker, err := fakers.NewBooleanFaker("Y/N", nil, rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		v, err := faker.Generate()
+		assert.NoError(t, err)
+		b := v.(models.Value)
+		assert.Equal(t, models.KindBool, b.Kind)
+		seen[b.Text] = b.Bool
+	}
+	assert.Equal(t, map[string]bool{"Y": true, "N": false}, seen)
+
+	// the json writer emits the tokens as JSON booleans
+	got, err := json.RenderJSONCell(`{"active": "${active:bool}"}`, map[string]string{"active": "Y"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"active": true}`, got)
+}
+
+func TestBooleanFaker_Invalid(t *testing.T) {
+	p := 1.5
+	_, err := fakers.NewBooleanFaker("", &p, rand.New(rand.NewSource(1)))
+	assert.ErrorContains(t, err, "probability must be between 0 and 1")
+
+	_, err = fakers.NewBooleanFaker("yes", nil, rand.New(rand.NewSource(1)))
+	assert.ErrorContains(t, err, "invalid format")
+}
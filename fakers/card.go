@@ -0,0 +1,228 @@
+package fakers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
+)
+
+type cardScheme struct {
+	length int
+	bins   []string
+}
+
+// test BINs published by the schemes/acquirers; these never route to a live issuer
+var cardSchemes = map[string]cardScheme{
+	"visa":       {length: 16, bins: []string{"411111", "424242", "400000", "401288"}},
+	"mastercard": {length: 16, bins: []string{"555555", "510510", "222300", "520082"}},
+	"amex":       {length: 15, bins: []string{"378282", "371449", "378734"}},
+	"discover":   {length: 16, bins: []string{"601111", "601100", "644564"}},
+}
+
+// cardExpiryBaseYear is fixed rather than the current year, so a seed produces
+// the same expiry dates whenever it is run. Expiries fall in the 5 years after it.
+const cardExpiryBaseYear = 2025
+
+type CardFaker struct {
+	datatype models.Type
+	format   string // "pan" (default) | "masked" | "expiry" | "cvv"
+	length   int
+	bins     []string
+	rng      *rand.Rand
+}
+
+func (f *CardFaker) Generate() (any, error) {
+	bin := f.bins[f.rng.Intn(len(f.bins))]
+	pan, err := luhnComplete(bin, f.length, f.rng)
+	if err != nil {
+		return nil, err
+	}
+	return CardVariant(pan, f.format)
+}
+
+func (f *CardFaker) GetType() models.Type { return f.datatype }
+func (f *CardFaker) GetFormat() string    { return f.format }
+
+// CardVariant derives the requested representation from a clear PAN. Expiry and
+// CVV are derived from a hash of the PAN so a reflected column always agrees
+// with the card it was taken from.
+func CardVariant(pan string, variant string) (string, error) {
+	pan = strings.TrimSpace(pan)
+	switch strings.ToLower(strings.TrimSpace(variant)) {
+	case "", "pan":
+		return pan, nil
+	case "masked":
+		return MaskPAN(pan), nil
+	case "expiry":
+		h := cardHash(pan)
+		month := int(h%12) + 1
+		year := cardExpiryBaseYear + 1 + int((h/12)%5)
+		return fmt.Sprintf("%02d/%02d", month, year%100), nil
+	case "cvv":
+		h := cardHash(pan)
+		if len(pan) == 15 {
+			return fmt.Sprintf("%04d", h%10000), nil
+		}
+		return fmt.Sprintf("%03d", h%1000), nil
+	default:
+		return "", fmt.Errorf("card: invalid format %q (expected \"pan\", \"masked\", \"expiry\" or \"cvv\")", variant)
+	}
+}
+
+// MaskPAN keeps the BIN and last four digits, e.g. 411111******1111
+func MaskPAN(pan string) string {
+	if len(pan) <= 10 {
+		return strings.Repeat("*", len(pan))
+	}
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
+
+// LuhnValid reports whether s is a digit string with a valid Luhn check digit.
+func LuhnValid(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func luhnCheckDigit(partial string) byte {
+	sum := 0
+	double := true
+	for i := len(partial) - 1; i >= 0; i-- {
+		d := int(partial[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func luhnComplete(prefix string, length int, rng *rand.Rand) (string, error) {
+	if len(prefix) >= length {
+		return "", fmt.Errorf("card: BIN %q is too long for a %d digit PAN", prefix, length)
+	}
+	b := make([]byte, 0, length)
+	b = append(b, prefix...)
+	for len(b) < length-1 {
+		b = append(b, byte('0'+rng.Intn(10)))
+	}
+	b = append(b, luhnCheckDigit(string(b)))
+	return string(b), nil
+}
+
+func cardHash(pan string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(pan))
+	return h.Sum64()
+}
+
+func NewCardFaker(format string, scheme string, bins string, length int, rng *rand.Rand) (*CardFaker, error) {
+	name := strings.ToLower(strings.TrimSpace(scheme))
+	if name == "" {
+		name = "visa"
+	}
+
+	var cfg cardScheme
+	if s, ok := cardSchemes[name]; ok {
+		cfg = s
+	} else if name != "custom" {
+		return nil, fmt.Errorf("card: unsupported scheme %q (expected visa, mastercard, amex, discover or custom)", scheme)
+	}
+
+	// custom BINs replace the scheme's test ranges but keep its length
+	if custom := cleanList(bins); len(custom) > 0 {
+		for _, bin := range custom {
+			for _, r := range bin {
+				if r < '0' || r > '9' {
+					return nil, fmt.Errorf("card: invalid BIN %q", bin)
+				}
+			}
+		}
+		cfg.bins = custom
+	}
+	if len(cfg.bins) == 0 {
+		return nil, fmt.Errorf("card: scheme %q requires a list of BINs in 'values'", name)
+	}
+
+	if length > 0 {
+		cfg.length = length
+	}
+	if cfg.length == 0 {
+		cfg.length = 16
+	}
+	if cfg.length < 12 || cfg.length > 19 {
+		return nil, fmt.Errorf("card: invalid length %d; must be between 12 and 19", cfg.length)
+	}
+
+	if _, err := CardVariant("", format); err != nil {
+		return nil, err
+	}
+
+	return &CardFaker{
+		datatype: models.Type("Card"),
+		format:   strings.ToLower(strings.TrimSpace(format)),
+		length:   cfg.length,
+		bins:     cfg.bins,
+		rng:      rng,
+	}, nil
+}
+
+func cleanList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if v := strings.TrimSpace(p); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func init() {
+	RegisterFaker("card", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
+		faker, err := NewCardFaker(field.Format, field.Scheme, field.Values, field.Length, rng)
+		if err != nil {
+			return nil, err
+		}
+		return faker, nil
+	})
+
+	DescribeFaker("card", FakerInfo{
+		Description: "Luhn-valid payment card numbers from published test BIN ranges.",
+		Attributes: []Attribute{
+			{Name: "scheme", Type: "string", Default: "visa", Description: "visa | mastercard | amex | discover | custom"},
+			{Name: "values", Type: "list", Description: "comma separated BIN prefixes, overriding the scheme's test BINs"},
+			{Name: "length", Type: "int", Description: "PAN length (defaults to the scheme's length)"},
+			{Name: "format", Type: "string", Default: "pan", Description: "pan | masked | expiry | cvv"},
+		},
+		Examples: []string{
+			`{ "name": "pan", "type": "card", "scheme": "mastercard" }`,
+			`{ "name": "expiry", "type": "reflection", "target": "pan", "format": "expiry" }`,
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package fakers_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardFaker_LuhnValidPerScheme(t *testing.T) {
+	tests := []struct {
+		scheme string
+		length int
+		prefix string
+	}{
+		{"visa", 16, "4"},
+		{"mastercard", 16, ""},
+		{"amex", 15, "3"},
+		{"discover", 16, "6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			faker, err := fakers.NewCardFaker("", tt.scheme, "", 0, rand.New(rand.NewSource(42)))
+			assert.NoError(t, err)
+
+			for i := 0; i < 50; i++ {
+				v, err := faker.Generate()
+				assert.NoError(t, err)
+				pan := v.(string)
+				assert.Len(t, pan, tt.length)
+				assert.True(t, fakers.LuhnValid(pan), "pan %s failed luhn", pan)
+				assert.True(t, strings.HasPrefix(pan, tt.prefix))
+			}
+		})
+	}
+}
+
+func TestCardFaker_CustomBins(t *testing.T) {
+	faker, err := fakers.NewCardFaker("", "custom", "492181", 19, rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+
+	v, err := faker.Generate()
+	assert.NoError(t, err)
+	pan := v.(string)
+	assert.Len(t, pan, 19)
+	assert.True(t, strings.HasPrefix(pan, "492181"))
+	assert.True(t, fakers.LuhnValid(pan))
+
+	_, err = fakers.NewCardFaker("", "custom", "", 0, rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
+
+func TestCardVariant(t *testing.T) {
+	masked, err := fakers.CardVariant("4111111111111111", "masked")
+	assert.NoError(t, err)
+	assert.Equal(t, "411111******1111", masked)
+
+	exp1, _ := fakers.CardVariant("4111111111111111", "expiry")
+	exp2, _ := fakers.CardVariant("4111111111111111", "expiry")
+	assert.Equal(t, exp1, exp2)
+	assert.Regexp(t, `^(0[1-9]|1[0-2])/(2[6-9]|30)$`, exp1, "expiries are in 2026-2030, not relative to today")
+
+	cvv, _ := fakers.CardVariant("378282246310005", "cvv")
+	assert.Len(t, cvv, 4)
+
+	_, err = fakers.CardVariant("4111111111111111", "pin")
+	assert.Error(t, err)
+}
@@ -58,4 +58,12 @@ func init() {
 	RegisterFaker("countrycode", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
 		return NewCountryCodeFaker(field.Format, rng), nil
 	})
+
+	DescribeFaker("countrycode", FakerInfo{
+		Description: "An ISO 3166-1 alpha-3 country code.",
+		Attributes: []Attribute{
+			{Name: "format", Type: "string", Description: "ignored; codes are always ISO 3166 alpha-3"},
+		},
+		Examples: []string{`{ "name": "country", "type": "countrycode" }`},
+	})
 }
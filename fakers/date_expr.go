@@ -0,0 +1,105 @@
+package fakers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006-01-02 15:04:05.999999999 -0700 MST", // time.Time.String(), how unformatted timestamps are written
+}
+
+// ParseDateExpr resolves an absolute date or a date expression to a time in loc.
+//
+// Supported forms:
+//   - absolute dates: RFC3339, "2006-01-02 15:04:05", "2006-01-02" or the field's own format
+//   - anchors: now, today, start_of_week, start_of_month, end_of_month, start_of_year, end_of_year
+//   - anchors with an offset: "today-30d", "start_of_month+12h", "now-1w"
+func ParseDateExpr(s string, layout string, loc *time.Location, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty date expression")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	layouts := dateLayouts
+	if layout != "" {
+		layouts = append([]string{layout}, dateLayouts...)
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, s, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	anchor, offset := s, ""
+	if i := strings.IndexAny(s, "+-"); i > 0 {
+		anchor, offset = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+	}
+
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var base time.Time
+	switch strings.ToLower(anchor) {
+	case "now":
+		base = now
+	case "today":
+		base = today
+	case "start_of_week":
+		// weeks start on Monday
+		base = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case "start_of_month":
+		base = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	case "end_of_month":
+		base = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	case "start_of_year":
+		base = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, loc)
+	case "end_of_year":
+		base = time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+	default:
+		return time.Time{}, fmt.Errorf("invalid date expression %q", s)
+	}
+
+	if offset == "" {
+		return base, nil
+	}
+
+	sign := time.Duration(1)
+	if offset[0] == '-' {
+		sign = -1
+	}
+	d := ParseDurationExt(strings.TrimSpace(offset[1:]), 0)
+	if d == 0 {
+		return time.Time{}, fmt.Errorf("invalid offset %q in date expression %q", offset, s)
+	}
+	return base.Add(sign * d), nil
+}
+
+// fakers are built per row, so cache zones rather than re-reading tzdata each time
+var locations sync.Map
+
+// LoadLocation resolves an IANA time zone name; empty means UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
@@ -0,0 +1,65 @@
+package fakers_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateExpr(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"2024-01-31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-31 12:30:00", time.Date(2024, 1, 31, 12, 30, 0, 0, time.UTC)},
+		{"now", now},
+		{"today", time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"today-30d", time.Date(2024, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{"start_of_week", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"start_of_month", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"start_of_month+12h", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"end_of_month", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)},
+		{"start_of_year", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := fakers.ParseDateExpr(tt.expr, "", time.UTC, now)
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+
+	_, err := fakers.ParseDateExpr("yesterday", "", time.UTC, now)
+	assert.Error(t, err)
+}
+
+func TestTimestampFaker_AbsoluteRange(t *testing.T) {
+	faker, err := fakers.NewTimestampFaker("2006-01-02", 0, rand.New(rand.NewSource(3)), "", "1940-01-01", "2006-12-31", "Europe/London")
+	assert.NoError(t, err)
+
+	lo := time.Date(1940, 1, 1, 0, 0, 0, 0, time.UTC)
+	hi := time.Date(2006, 12, 31, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		v, err := faker.Generate()
+		assert.NoError(t, err)
+		ts := v.(models.Value)
+		assert.Equal(t, models.KindTimestamp, ts.Kind)
+		d, err := time.Parse("2006-01-02", ts.String())
+		assert.NoError(t, err)
+		assert.False(t, d.Before(lo) || d.After(hi), "date %s out of range", d)
+	}
+
+	_, err = fakers.NewTimestampFaker("", 0, nil, "", "2020-01-01", "", "")
+	assert.Error(t, err)
+
+	_, err = fakers.NewTimestampFaker("", 0, nil, "", "2020-01-01", "2019-01-01", "")
+	assert.Error(t, err)
+}
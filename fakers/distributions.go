@@ -0,0 +1,186 @@
+package fakers
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Sample is a single draw from a function string. Normalized samples lie in
+// [0,1] and are scaled into the field's range; absolute samples are already in
+// field units (e.g. normal:mean=100,stddev=15) and are used as-is.
+type Sample struct {
+	Value    float64
+	Absolute bool
+}
+
+type distribution func(params map[string]string, rng *rand.Rand) float64
+
+// distributions that return absolute values rather than a position in [0,1]
+var distributions = map[string]distribution{
+	"normal":    sampleNormal,
+	"lognormal": sampleLogNormal,
+	"poisson":   samplePoisson,
+	"binomial":  sampleBinomial,
+	"zipf":      sampleZipf,
+	"pareto":    samplePareto,
+	"empirical": sampleEmpirical,
+}
+
+// sampleFunction draws from fn, dispatching to the absolute distributions or
+// the normalized sampler.
+func sampleFunction(fn string, params map[string]string, rng *rand.Rand, t float64) Sample {
+	if d, ok := distributions[fn]; ok {
+		return Sample{Value: d(params, rng), Absolute: true}
+	}
+	return Sample{Value: sampleNormalized(fn, params, rng, t)}
+}
+
+func uniform(rng *rand.Rand) float64 {
+	if rng != nil {
+		return rng.Float64()
+	}
+	return rand.Float64()
+}
+
+func stdNormal(rng *rand.Rand) float64 {
+	if rng != nil {
+		return rng.NormFloat64()
+	}
+	return rand.NormFloat64()
+}
+
+// normal: mean (default 0), stddev (default 1)
+func sampleNormal(params map[string]string, rng *rand.Rand) float64 {
+	mean := parseFloat(params["mean"], 0.0)
+	stddev := math.Abs(parseFloat(params["stddev"], 1.0))
+	return mean + stddev*stdNormal(rng)
+}
+
+// lognormal: mu (default 0), sigma (default 1) of the underlying normal
+func sampleLogNormal(params map[string]string, rng *rand.Rand) float64 {
+	mu := parseFloat(params["mu"], 0.0)
+	sigma := math.Abs(parseFloat(params["sigma"], 1.0))
+	return math.Exp(mu + sigma*stdNormal(rng))
+}
+
+// poisson: lambda (default 1). Knuth's method for small lambda, normal
+// approximation above 30 where it becomes slow.
+func samplePoisson(params map[string]string, rng *rand.Rand) float64 {
+	lambda := parseFloat(params["lambda"], 1.0)
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		return math.Max(0, math.Round(lambda+math.Sqrt(lambda)*stdNormal(rng)))
+	}
+	l := math.Exp(-lambda)
+	k := 0.0
+	p := 1.0
+	for {
+		p *= uniform(rng)
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
+
+// binomial: n trials (default 10), p success probability (default 0.5)
+func sampleBinomial(params map[string]string, rng *rand.Rand) float64 {
+	n := int(parseFloat(params["n"], 10))
+	p := math.Max(0, math.Min(1, parseFloat(params["p"], 0.5)))
+	if n <= 0 {
+		return 0
+	}
+	if n > 1000 {
+		mean := float64(n) * p
+		sd := math.Sqrt(mean * (1 - p))
+		return math.Max(0, math.Min(float64(n), math.Round(mean+sd*stdNormal(rng))))
+	}
+	k := 0
+	for i := 0; i < n; i++ {
+		if uniform(rng) < p {
+			k++
+		}
+	}
+	return float64(k)
+}
+
+// zipf: s exponent (> 1, default 1.1), v (>= 1, default 1), n largest rank
+// (default 1000). Returns a rank in [1,n], rank 1 being the most frequent.
+func sampleZipf(params map[string]string, rng *rand.Rand) float64 {
+	s := parseFloat(params["s"], 1.1)
+	if s <= 1 {
+		s = 1.1
+	}
+	v := parseFloat(params["v"], 1.0)
+	if v < 1 {
+		v = 1
+	}
+	n := uint64(math.Max(1, parseFloat(params["n"], 1000)))
+	src := rng
+	if src == nil {
+		src = rand.New(rand.NewSource(rand.Int63()))
+	}
+	return float64(rand.NewZipf(src, s, v, n-1).Uint64() + 1)
+}
+
+// pareto: alpha shape (default 1.16, the 80/20 rule), xm scale/minimum (default 1)
+func samplePareto(params map[string]string, rng *rand.Rand) float64 {
+	alpha := parseFloat(params["alpha"], 1.16)
+	if alpha <= 0 {
+		alpha = 1.16
+	}
+	xm := parseFloat(params["xm"], 1.0)
+	u := 1.0 - uniform(rng) // (0,1]
+	return xm / math.Pow(u, 1.0/alpha)
+}
+
+// sampleBeta draws from Beta(a,b) in [0,1] via two gamma draws.
+func sampleBeta(params map[string]string, rng *rand.Rand) float64 {
+	a := parseFloat(params["a"], 2.0)
+	b := parseFloat(params["b"], 2.0)
+	if a <= 0 {
+		a = 2
+	}
+	if b <= 0 {
+		b = 2
+	}
+	x := sampleGamma(a, rng)
+	y := sampleGamma(b, rng)
+	if x+y == 0 {
+		return 0.5
+	}
+	return x / (x + y)
+}
+
+// sampleGamma uses Marsaglia and Tsang's method; shape < 1 is boosted and corrected.
+func sampleGamma(shape float64, rng *rand.Rand) float64 {
+	if shape < 1 {
+		return sampleGamma(shape+1, rng) * math.Pow(uniform(rng), 1/shape)
+	}
+	d := shape - 1.0/3.0
+	c := 1.0 / math.Sqrt(9*d)
+	for {
+		x := stdNormal(rng)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := uniform(rng)
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// sampleTriangular draws in [0,1] with its peak at mode (a fraction of the range, default 0.5).
+func sampleTriangular(params map[string]string, rng *rand.Rand) float64 {
+	c := math.Max(0, math.Min(1, parseFloat(params["mode"], 0.5)))
+	u := uniform(rng)
+	if u < c {
+		return math.Sqrt(u * c)
+	}
+	return 1 - math.Sqrt((1-u)*(1-c))
+}
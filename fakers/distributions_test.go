@@ -0,0 +1,80 @@
+package fakers_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/stretchr/testify/assert"
+)
+
+func drawNumbers(t *testing.T, function string, min, max float64, seed int64, n int) []float64 {
+	t.Helper()
+	faker, err := fakers.NewNumberFaker("", 0, min, max, rand.New(rand.NewSource(seed)), function)
+	assert.NoError(t, err)
+
+	out := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := faker.Generate()
+		assert.NoError(t, err)
+		out = append(out, v.(float64))
+	}
+	return out
+}
+
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func TestDistributions_MeanAndBounds(t *testing.T) {
+	tests := []struct {
+		function string
+		min, max float64
+		mean     float64
+		tol      float64
+	}{
+		{"normal:mean=100,stddev=15", 0, 1000, 100, 2},
+		{"normal:mean=100,stddev=50", 80, 120, 100, 2},
+		{"lognormal:mu=2,sigma=0.5", 0, 1000, math.Exp(2 + 0.125), 0.5},
+		{"poisson:lambda=4", 0, 100, 4, 0.2},
+		{"poisson:lambda=200", 0, 1000, 200, 2},
+		{"binomial:n=20,p=0.25", 0, 20, 5, 0.2},
+		{"pareto:alpha=3,xm=10", 0, 1e9, 15, 1},
+		{"beta:a=2,b=6", 0, 100, 25, 1},
+		{"triangular:mode=0.2", 0, 90, 36, 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			xs := drawNumbers(t, tt.function, tt.min, tt.max, 11, 5000)
+			for _, x := range xs {
+				assert.True(t, x >= tt.min && x <= tt.max, "%v outside [%v,%v]", x, tt.min, tt.max)
+			}
+			assert.InDelta(t, tt.mean, mean(xs), tt.tol)
+		})
+	}
+}
+
+func TestDistributions_Zipf(t *testing.T) {
+	xs := drawNumbers(t, "zipf:s=2,n=50", 1, 50, 5, 2000)
+	counts := map[float64]int{}
+	for _, x := range xs {
+		assert.True(t, x >= 1 && x <= 50)
+		counts[x]++
+	}
+	assert.Greater(t, counts[1], counts[2])
+	assert.Greater(t, counts[2], counts[5])
+}
+
+func TestDistributions_SeededDeterminism(t *testing.T) {
+	for _, fn := range []string{"normal:mean=5", "lognormal", "poisson:lambda=3", "binomial", "zipf", "pareto", "beta", "triangular"} {
+		a := drawNumbers(t, fn, 0, 1e6, 99, 50)
+		b := drawNumbers(t, fn, 0, 1e6, 99, 50)
+		assert.Equal(t, a, b, fn)
+	}
+}
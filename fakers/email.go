@@ -47,7 +47,6 @@ func NewEmailFaker(format string, rng *rand.Rand) *EmailFaker {
 	}
 }
 
-
 func (f *EmailFaker) RandomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
 	result := make([]byte, length)
@@ -61,4 +60,12 @@ func init() {
 	RegisterFaker("email", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
 		return NewEmailFaker(field.Format, rng), nil
 	})
+
+	DescribeFaker("email", FakerInfo{
+		Description: "A random email address on a common domain.",
+		Attributes: []Attribute{
+			{Name: "format", Type: "string", Description: "ignored; addresses are always an 8-character name at a sample domain"},
+		},
+		Examples: []string{`{ "name": "email", "type": "email" }`},
+	})
 }
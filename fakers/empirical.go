@@ -0,0 +1,155 @@
+package fakers
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// empiricalPoints is how many quantiles EmpiricalFunction keeps (every 5%).
+const empiricalPoints = 21
+
+// sampleEmpirical draws from a distribution fitted to real data, given either as
+//   - quantiles=v0|v1|...|vn: values at evenly spaced quantiles (v0 the minimum, vn the maximum)
+//   - bins=e0|e1|...|ek with weights=w1|...|wk: a histogram of k bins
+//
+// Draws are interpolated linearly between quantiles, or uniformly within a bin.
+func sampleEmpirical(params map[string]string, rng *rand.Rand) float64 {
+	if q, err := parseFloatList(params["quantiles"]); err == nil && len(q) > 0 {
+		if len(q) == 1 {
+			return q[0]
+		}
+		pos := uniform(rng) * float64(len(q)-1)
+		i := int(pos)
+		if i >= len(q)-1 {
+			return q[len(q)-1]
+		}
+		return q[i] + (pos-float64(i))*(q[i+1]-q[i])
+	}
+
+	edges, err := parseFloatList(params["bins"])
+	if err != nil || len(edges) < 2 {
+		return 0
+	}
+	weights, err := parseFloatList(params["weights"])
+	if err != nil || len(weights) != len(edges)-1 {
+		weights = make([]float64, len(edges)-1)
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	r := uniform(rng) * total
+	bin := len(weights) - 1
+	for i, w := range weights {
+		if r < w {
+			bin = i
+			break
+		}
+		r -= w
+	}
+	return edges[bin] + uniform(rng)*(edges[bin+1]-edges[bin])
+}
+
+// validateEmpirical checks the quantile or histogram table when the function string is parsed.
+func validateEmpirical(params map[string]string) error {
+	if raw, ok := params["quantiles"]; ok {
+		q, err := parseFloatList(raw)
+		if err != nil {
+			return fmt.Errorf("empirical: quantiles: %w", err)
+		}
+		if !sort.Float64sAreSorted(q) {
+			return fmt.Errorf("empirical: quantiles must be in ascending order")
+		}
+		return nil
+	}
+
+	raw, ok := params["bins"]
+	if !ok {
+		return fmt.Errorf("empirical: requires quantiles or bins")
+	}
+	edges, err := parseFloatList(raw)
+	if err != nil {
+		return fmt.Errorf("empirical: bins: %w", err)
+	}
+	if len(edges) < 2 {
+		return fmt.Errorf("empirical: bins needs at least two edges")
+	}
+	if !sort.Float64sAreSorted(edges) {
+		return fmt.Errorf("empirical: bins must be in ascending order")
+	}
+	if raw, ok := params["weights"]; ok {
+		weights, err := parseFloatList(raw)
+		if err != nil {
+			return fmt.Errorf("empirical: weights: %w", err)
+		}
+		if len(weights) != len(edges)-1 {
+			return fmt.Errorf("empirical: %d bins need %d weights, got %d", len(edges)-1, len(edges)-1, len(weights))
+		}
+		for _, w := range weights {
+			if w < 0 {
+				return fmt.Errorf("empirical: weights must not be negative")
+			}
+		}
+	}
+	return nil
+}
+
+// EmpiricalFunction fits a quantile table to values and returns it as a function
+// string, e.g. "empirical:quantiles=1|4.5|9|...|120". decimals rounds the quantiles
+// to the precision of the source data.
+func EmpiricalFunction(values []float64, decimals int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	points := empiricalPoints
+	if len(sorted) < points {
+		points = len(sorted)
+	}
+
+	parts := make([]string, 0, points)
+	for i := 0; i < points; i++ {
+		p := 0.0
+		if points > 1 {
+			p = float64(i) / float64(points-1)
+		}
+		parts = append(parts, strconv.FormatFloat(quantile(sorted, p), 'f', decimals, 64))
+	}
+	return "empirical:quantiles=" + strings.Join(parts, "|")
+}
+
+// quantile interpolates linearly between the closest ranks of sorted.
+func quantile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i := int(math.Floor(pos))
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+func parseFloatList(s string) ([]float64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("empty list")
+	}
+	parts := strings.Split(s, "|")
+	out := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
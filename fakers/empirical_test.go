@@ -0,0 +1,61 @@
+package fakers_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmpirical_Quantiles(t *testing.T) {
+	xs := drawNumbers(t, "empirical:quantiles=0|10|20|100", 0, 100, 3, 6000)
+	sort.Float64s(xs)
+	for _, x := range xs {
+		assert.True(t, x >= 0 && x <= 100)
+	}
+	// each segment between quantiles holds a third of the draws
+	assert.InDelta(t, 10.0, xs[len(xs)/3], 1)
+	assert.InDelta(t, 20.0, xs[2*len(xs)/3], 1)
+}
+
+func TestEmpirical_Histogram(t *testing.T) {
+	xs := drawNumbers(t, "empirical:bins=0|10|100,weights=3|1", 0, 100, 3, 4000)
+	low := 0
+	for _, x := range xs {
+		if x < 10 {
+			low++
+		}
+	}
+	assert.InDelta(t, 0.75, float64(low)/float64(len(xs)), 0.03)
+}
+
+func TestEmpirical_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empirical":                        "requires quantiles or bins",
+		"empirical:quantiles=5|1":          "ascending order",
+		"empirical:quantiles=1|x":          `invalid number "x"`,
+		"empirical:bins=0|10|20,weights=1": "2 bins need 2 weights, got 1",
+		"empirical:bins=0|10,weights=-1":   "must not be negative",
+		"empirical:quantiles=1|2 + random": "cannot combine absolute distributions",
+		"empirical:bins=0":                 "at least two edges",
+	}
+	for fn, msg := range tests {
+		_, err := fakers.NewNumberFaker("", 0, 0, 100, nil, fn)
+		if assert.Error(t, err, fn) {
+			assert.Contains(t, err.Error(), msg, fn)
+		}
+	}
+}
+
+func TestEmpiricalFunction(t *testing.T) {
+	assert.Equal(t, "empirical:quantiles=1|2|3", fakers.EmpiricalFunction([]float64{3, 1, 2}, 0))
+
+	values := make([]float64, 101)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	fn := fakers.EmpiricalFunction(values, 1)
+	assert.Contains(t, fn, "empirical:quantiles=0.0|5.0|10.0|")
+	assert.Contains(t, fn, "|95.0|100.0")
+}
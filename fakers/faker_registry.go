@@ -1,7 +1,11 @@
 package fakers
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/kream404/spoof/interfaces"
@@ -11,21 +15,176 @@ import (
 
 type FakerFactory func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error)
 
-var registry = make(map[string]FakerFactory)
+// Attribute is a models.Field attribute a faker reads.
+type Attribute struct {
+	Name        string // json name in the config, e.g. "min"
+	Type        string // string | int | number | bool | duration | date | list
+	Default     string
+	Description string
+}
+
+// FakerInfo describes a registered faker for `spoof fakers` and config validation.
+type FakerInfo struct {
+	Name        string
+	Description string
+	Attributes  []Attribute
+	Functions   []string // accepted by the function attribute
+	Examples    []string // field configs, as JSON
+
+	// AnyAttributes disables attribute validation, for fakers that are handed the whole field (plugins)
+	AnyAttributes bool
+}
+
+type registration struct {
+	factory FakerFactory
+	info    *FakerInfo
+}
+
+var registry = make(map[string]*registration)
 var mu sync.Mutex
 
+// fieldAttributes are handled by the evaluator for every field type.
+var fieldAttributes = map[string]struct{}{
+	"name": {}, "alias": {}, "type": {}, "modifier": {}, "auto_increment": {}, "foreign_key": {},
+	"seed": {}, "selector": {}, "source": {}, "rate": {}, "null_rate": {}, "skip": {},
+	"width": {}, "align": {}, "pad_char": {}, "truncate": {},
+}
+
 func RegisterFaker(name string, factory FakerFactory) {
 	mu.Lock()
 	defer mu.Unlock()
-	registry[name] = factory
+	if r, ok := registry[name]; ok {
+		r.factory = factory
+		return
+	}
+	registry[name] = &registration{factory: factory}
+}
+
+// DescribeFaker attaches metadata to a faker registered under name.
+func DescribeFaker(name string, info FakerInfo) {
+	mu.Lock()
+	defer mu.Unlock()
+	info.Name = name
+	r, ok := registry[name]
+	if !ok {
+		r = &registration{}
+		registry[name] = r
+	}
+	r.info = &info
 }
 
 func GetFakerByName(name string) (FakerFactory, bool) {
 	mu.Lock()
 	defer mu.Unlock()
-	factory, found := registry[name]
-	if !found {
+	r, found := registry[name]
+	if !found || r.factory == nil {
 		log.Error("Unsupported faker")
+		return nil, false
+	}
+	return r.factory, true
+}
+
+// GetFakerInfo returns the metadata for a registered faker. Fakers that were
+// never described get an info with only their name.
+func GetFakerInfo(name string) (FakerInfo, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	r, found := registry[name]
+	if !found || r.factory == nil {
+		return FakerInfo{}, false
+	}
+	if r.info == nil {
+		return FakerInfo{Name: name, AnyAttributes: true}, true
+	}
+	return *r.info, true
+}
+
+// ListFakers returns every registered faker, sorted by name.
+func ListFakers() []FakerInfo {
+	mu.Lock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	mu.Unlock()
+
+	sort.Strings(names)
+	out := make([]FakerInfo, 0, len(names))
+	for _, name := range names {
+		if info, ok := GetFakerInfo(name); ok {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// FunctionNames lists every function usable in a function string.
+func FunctionNames() []string {
+	names := make([]string, 0, len(normalizedFunctions)+len(distributions))
+	for n := range normalizedFunctions {
+		names = append(names, n)
+	}
+	for n := range distributions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateField reports attributes set on a faker field that its faker does not read,
+// e.g. regex on a number. Fields of non-faker types (iterator, json, ...) are not checked.
+func ValidateField(field models.Field) error {
+	info, ok := GetFakerInfo(field.Type)
+	if !ok || info.AnyAttributes {
+		return nil
+	}
+
+	raw, err := json.Marshal(field)
+	if err != nil {
+		return err
+	}
+	var set map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return err
+	}
+
+	supported := make(map[string]struct{}, len(info.Attributes))
+	names := make([]string, 0, len(info.Attributes))
+	for _, a := range info.Attributes {
+		supported[a.Name] = struct{}{}
+		names = append(names, a.Name)
+	}
+
+	var unsupported []string
+	for key := range set {
+		if _, ok := fieldAttributes[key]; ok {
+			continue
+		}
+		if _, ok := supported[key]; ok {
+			continue
+		}
+		unsupported = append(unsupported, key)
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+
+	sort.Strings(unsupported)
+	hint := "none"
+	if len(names) > 0 {
+		hint = strings.Join(names, ", ")
+	}
+	return fmt.Errorf("field %q: %s not supported by %s (supported: %s)",
+		field.Name, quoteList(unsupported), field.Type, hint)
+}
+
+func quoteList(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = fmt.Sprintf("%q", k)
+	}
+	if len(quoted) == 1 {
+		return "attribute " + quoted[0] + " is"
 	}
-	return factory, found
+	return "attributes " + strings.Join(quoted, ", ") + " are"
 }
@@ -0,0 +1,50 @@
+package fakers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateField(t *testing.T) {
+	tests := []struct {
+		field   models.Field
+		message string
+	}{
+		{models.Field{Name: "amount", Type: "number", Min: "1", Max: "9", Format: "2", Seed: true, Alias: "amt"}, ""},
+		{models.Field{Name: "amount", Type: "number", Min: "1", Max: "9", Regex: "[0-9]+"}, `field "amount": attribute "regex" is not supported by number`},
+		{models.Field{Name: "id", Type: "uuid", Format: "x", Length: 3}, `attribute "length" is not supported by uuid (supported: format)`},
+		{models.Field{Name: "email", Type: "email", Format: "email", Seed: true}, ""},
+		{models.Field{Name: "n", Type: "iterator", Regex: "ignored"}, ""}, // not a faker
+	}
+	for _, tt := range tests {
+		err := fakers.ValidateField(tt.field)
+		if tt.message == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.ErrorContains(t, err, tt.message)
+	}
+}
+
+func TestFakerInfo(t *testing.T) {
+	for _, info := range fakers.ListFakers() {
+		if strings.HasPrefix(info.Description, "External plugin ") {
+			continue // plugins registered by other tests
+		}
+		// a built-in without DescribeFaker would skip attribute validation
+		assert.False(t, info.AnyAttributes, info.Name)
+		assert.NotEmpty(t, info.Description, info.Name)
+		assert.NotEmpty(t, info.Examples, info.Name)
+		// every factory reads field.Format, so every faker must accept it
+		assert.NoError(t, fakers.ValidateField(models.Field{Name: "f", Type: info.Name, Format: "x"}), info.Name)
+	}
+
+	info, ok := fakers.GetFakerInfo("timestamp")
+	assert.True(t, ok)
+	assert.Contains(t, info.Functions, "sin")
+	assert.Contains(t, info.Functions, "normal")
+}
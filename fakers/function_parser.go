@@ -0,0 +1,325 @@
+package fakers
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kream404/spoof/models"
+)
+
+// FunctionTerm is one weighted function call in a function string, e.g. "sin:period=7d*0.3".
+type FunctionTerm struct {
+	Name     string
+	Params   map[string]string
+	Weight   float64
+	weighted bool
+}
+
+// FunctionExpr is a parsed function string: one or more terms summed together,
+// e.g. "linear:period=90d*0.5 + sin:period=7d*0.3 + random*0.2".
+type FunctionExpr struct {
+	Source string
+	Terms  []FunctionTerm
+}
+
+// normalized functions handled by sampleNormalized; absolute ones live in distributions
+var normalizedFunctions = map[string]struct{}{
+	"random": {}, "sin": {}, "linear": {}, "constant": {}, "exponential": {}, "beta": {}, "triangular": {},
+}
+
+func isKnownFunction(name string) bool {
+	if _, ok := normalizedFunctions[name]; ok {
+		return true
+	}
+	_, ok := distributions[name]
+	return ok
+}
+
+// parseFunctionString parses a function string into its weighted terms.
+//
+//	expr   := term ( "+" term )*
+//	term   := [ number "*" ] call [ "*" number ]
+//	call   := name [ ":" param ( ("," | ";") param )* ]
+//	param  := key "=" value
+//
+// Terms without a weight share equally (1/n), so a single function behaves as before.
+func parseFunctionString(s string) (*FunctionExpr, error) {
+	p := &functionParser{src: s}
+	expr := &FunctionExpr{Source: s}
+
+	p.skipSpace()
+	if p.done() {
+		return expr, nil
+	}
+
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		expr.Terms = append(expr.Terms, term)
+
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		if p.peek() != '+' {
+			return nil, p.errorf("expected '+' between functions, got %q", p.peek())
+		}
+		p.pos++
+	}
+
+	absolute := 0
+	for _, t := range expr.Terms {
+		if _, ok := distributions[t.Name]; ok {
+			absolute++
+		}
+	}
+	if absolute > 0 && absolute < len(expr.Terms) {
+		return nil, fmt.Errorf("function %q: cannot combine absolute distributions with normalized functions", s)
+	}
+
+	for _, t := range expr.Terms {
+		if t.Name == "empirical" {
+			if err := validateEmpirical(t.Params); err != nil {
+				return nil, fmt.Errorf("function %q: %w", s, err)
+			}
+		}
+		switch strings.ToLower(t.Params["basis"]) {
+		case "", "clock", "row":
+		case "event_time":
+			if t.Params["event_field"] == "" {
+				return nil, fmt.Errorf("function %q: basis=event_time requires an event_field", s)
+			}
+		default:
+			return nil, fmt.Errorf("function %q: invalid basis %q (expected clock, row or event_time)", s, t.Params["basis"])
+		}
+	}
+
+	for i := range expr.Terms {
+		if !expr.Terms[i].weighted {
+			expr.Terms[i].Weight = 1.0 / float64(len(expr.Terms))
+		}
+	}
+
+	return expr, nil
+}
+
+// Params returns the mapping modifiers (amplitude, center, clamp, dir, interval, unit, ...)
+// for the expression. For composites the first term that sets a key wins.
+func (e *FunctionExpr) Params() map[string]string {
+	out := make(map[string]string)
+	for _, t := range e.Terms {
+		for k, v := range t.Params {
+			if _, ok := out[k]; !ok {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// Single returns the only term of a non-composite expression.
+func (e *FunctionExpr) Single() (FunctionTerm, bool) {
+	if len(e.Terms) != 1 {
+		return FunctionTerm{}, false
+	}
+	return e.Terms[0], true
+}
+
+// sample draws every term and returns their weighted sum.
+func (e *FunctionExpr) sample(rng *rand.Rand, row models.Row) (Sample, error) {
+	var out Sample
+	for _, term := range e.Terms {
+		t, err := functionTime(term.Params, row)
+		if err != nil {
+			return Sample{}, fmt.Errorf("function %q: %w", e.Source, err)
+		}
+		s := sampleFunction(term.Name, term.Params, rng, t)
+		out.Value += term.Weight * s.Value
+		out.Absolute = s.Absolute
+	}
+	return out, nil
+}
+
+// functionTime is the position time-based functions (sin, linear) are evaluated at:
+//   - basis=clock (default): the current unix time in seconds
+//   - basis=row: the row index, so period is a number of rows
+//   - basis=event_time: the unix time of the timestamp field named by event_field, generated
+//     earlier in the row; other values are parsed as dates, with the layout in event_format if set
+func functionTime(params map[string]string, row models.Row) (float64, error) {
+	switch strings.ToLower(params["basis"]) {
+	case "row":
+		return float64(row.Index), nil
+	case "event_time":
+		name := params["event_field"]
+		if row.Value != nil {
+			if v, ok := row.Value(name); ok && v.Kind == models.KindTimestamp {
+				return float64(v.Time.UnixNano()) / 1e9, nil
+			}
+		}
+		var raw string
+		ok := false
+		if row.Lookup != nil {
+			raw, ok = row.Lookup(name)
+		}
+		if !ok {
+			return 0, fmt.Errorf("basis=event_time: field %q has not been generated yet; it must come before this field", name)
+		}
+		t, err := ParseDateExpr(raw, params["event_format"], time.UTC, time.Now())
+		if err != nil {
+			return 0, fmt.Errorf("basis=event_time: field %q: %w", name, err)
+		}
+		return float64(t.UnixNano()) / 1e9, nil
+	default:
+		return float64(time.Now().UnixNano()) / 1e9, nil
+	}
+}
+
+type functionParser struct {
+	src string
+	pos int
+}
+
+func (p *functionParser) done() bool { return p.pos >= len(p.src) }
+func (p *functionParser) peek() byte { return p.src[p.pos] }
+
+func (p *functionParser) skipSpace() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n') {
+		p.pos++
+	}
+}
+
+func (p *functionParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("function %q: at position %d: %s", p.src, p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *functionParser) parseTerm() (FunctionTerm, error) {
+	term := FunctionTerm{Params: make(map[string]string)}
+
+	p.skipSpace()
+	if p.done() {
+		return term, p.errorf("expected a function name")
+	}
+
+	// leading weight: "0.3*sin"
+	if c := p.peek(); c == '.' || c == '-' || (c >= '0' && c <= '9') {
+		w, err := p.parseNumber()
+		if err != nil {
+			return term, err
+		}
+		p.skipSpace()
+		if p.done() || p.peek() != '*' {
+			return term, p.errorf("expected '*' after weight %v", w)
+		}
+		p.pos++
+		term.Weight, term.weighted = w, true
+		p.skipSpace()
+	}
+
+	term.Name = strings.ToLower(p.parseIdent())
+	if term.Name == "" {
+		if p.done() {
+			return term, p.errorf("expected a function name")
+		}
+		return term, p.errorf("expected a function name, got %q", p.peek())
+	}
+	if !isKnownFunction(term.Name) {
+		return term, p.errorf("unknown function %q", term.Name)
+	}
+
+	p.skipSpace()
+	if !p.done() && p.peek() == ':' {
+		p.pos++
+		if err := p.parseParams(term.Params); err != nil {
+			return term, err
+		}
+	}
+
+	// trailing weight: "sin:period=7d*0.3"
+	p.skipSpace()
+	if !p.done() && p.peek() == '*' {
+		if term.weighted {
+			return term, p.errorf("function %q already has a weight", term.Name)
+		}
+		p.pos++
+		p.skipSpace()
+		w, err := p.parseNumber()
+		if err != nil {
+			return term, err
+		}
+		term.Weight, term.weighted = w, true
+	}
+
+	return term, nil
+}
+
+func (p *functionParser) parseParams(params map[string]string) error {
+	for {
+		p.skipSpace()
+		key := strings.ToLower(p.parseIdent())
+		if key == "" {
+			if p.done() {
+				return p.errorf("expected a parameter name")
+			}
+			return p.errorf("expected a parameter name, got %q", p.peek())
+		}
+		p.skipSpace()
+		if p.done() || p.peek() != '=' {
+			return p.errorf("expected '=' after parameter %q", key)
+		}
+		p.pos++
+		p.skipSpace()
+
+		start := p.pos
+		for !p.done() && !strings.ContainsRune(",;*+ \t\n", rune(p.peek())) {
+			p.pos++
+		}
+		value := p.src[start:p.pos]
+		if value == "" {
+			return p.errorf("missing value for parameter %q", key)
+		}
+		params[key] = value
+
+		p.skipSpace()
+		if p.done() || (p.peek() != ',' && p.peek() != ';') {
+			return nil
+		}
+		p.pos++
+	}
+}
+
+func (p *functionParser) parseIdent() string {
+	start := p.pos
+	for !p.done() {
+		c := p.peek()
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *functionParser) parseNumber() (float64, error) {
+	start := p.pos
+	for !p.done() && strings.ContainsRune("0123456789.-eE", rune(p.peek())) {
+		p.pos++
+	}
+	raw := p.src[start:p.pos]
+	w, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		// report the whole offending token, not just its numeric prefix
+		end := p.pos
+		for end < len(p.src) && !strings.ContainsRune("+* \t\n", rune(p.src[end])) {
+			end++
+		}
+		p.pos = start
+		return 0, p.errorf("invalid weight %q", p.src[start:end])
+	}
+	return w, nil
+}
@@ -0,0 +1,131 @@
+package fakers_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionString_Valid(t *testing.T) {
+	valid := []string{
+		"random",
+		"sin:period=7d,amplitude=1.5,center=50,jitter=0.005,jitter_type=scale,jitter_amp=3",
+		"constant:dir=past;interval=52w",
+		"linear:period=90d*0.5 + sin:period=7d*0.3 + random*0.2",
+		"0.7*linear:period=30d + 0.3*random",
+		"sin:period=7d + sin:period=1d",
+		"normal:mean=10,stddev=2*0.5 + poisson:lambda=4*0.5",
+	}
+	for _, fn := range valid {
+		_, err := fakers.NewNumberFaker("", 0, 0, 100, rand.New(rand.NewSource(1)), fn)
+		assert.NoError(t, err, fn)
+	}
+}
+
+func TestFunctionString_Errors(t *testing.T) {
+	tests := []struct {
+		function string
+		message  string
+	}{
+		{"sine:period=7d", `unknown function "sine"`},
+		{"sin:period", `expected '=' after parameter "period"`},
+		{"sin:period=", `missing value for parameter "period"`},
+		{"sin:period=7d random", `expected '+' between functions`},
+		{"sin*abc", `invalid weight "abc"`},
+		{"sin + ", `expected a function name`},
+		{"0.5*sin*0.5", `already has a weight`},
+		{"normal:mean=5 + random", `cannot combine absolute distributions`},
+		{"sin:basis=rows", `invalid basis "rows"`},
+		{"sin:basis=event_time", `requires an event_field`},
+	}
+	for _, tt := range tests {
+		_, err := fakers.NewNumberFaker("", 0, 0, 100, rand.New(rand.NewSource(1)), tt.function)
+		if assert.Error(t, err, tt.function) {
+			assert.Contains(t, err.Error(), tt.message)
+		}
+	}
+}
+
+func TestFunctionString_CompositeWeights(t *testing.T) {
+	// constant terms make the weighted sum exact: 0.25*0 + 0.75*1 mapped onto [0,100]
+	faker, err := fakers.NewNumberFaker("", 0, 0, 100, rand.New(rand.NewSource(1)),
+		"constant:valuenorm=0*0.25 + constant:valuenorm=1*0.75")
+	assert.NoError(t, err)
+
+	v, err := faker.Generate()
+	assert.NoError(t, err)
+	assert.InDelta(t, 75.0, v.(float64), 1e-9)
+
+	// unweighted terms share equally
+	faker, err = fakers.NewNumberFaker("", 0, 0, 100, rand.New(rand.NewSource(1)),
+		"constant:valuenorm=0 + constant:valuenorm=1")
+	assert.NoError(t, err)
+
+	v, err = faker.Generate()
+	assert.NoError(t, err)
+	assert.InDelta(t, 50.0, v.(float64), 1e-9)
+}
+
+func TestFunctionString_RowBasis(t *testing.T) {
+	draw := func() []float64 {
+		faker, err := fakers.NewNumberFaker("", 0, 0, 100, nil, "sin:basis=row,period=100")
+		assert.NoError(t, err)
+		out := make([]float64, 0, 100)
+		for i := 0; i < 100; i++ {
+			faker.SetRow(models.Row{Index: i})
+			v, err := faker.Generate()
+			assert.NoError(t, err)
+			out = append(out, v.(float64))
+		}
+		return out
+	}
+
+	xs := draw()
+	assert.Equal(t, xs, draw())
+	assert.InDelta(t, 50.0, xs[0], 1e-9)   // sin(0)
+	assert.InDelta(t, 100.0, xs[25], 1e-9) // peak a quarter of the way through the period
+	assert.InDelta(t, 0.0, xs[75], 1e-9)
+}
+
+func TestFunctionString_EventTimeBasis(t *testing.T) {
+	faker, err := fakers.NewNumberFaker("", 0, 0, 100, nil, "linear:basis=event_time,event_field=created,period=1d")
+	assert.NoError(t, err)
+
+	faker.SetRow(models.Row{Lookup: func(name string) (string, bool) {
+		if name == "created" {
+			return "2024-03-01T18:00:00Z", true
+		}
+		return "", false
+	}})
+	v, err := faker.Generate()
+	assert.NoError(t, err)
+	assert.InDelta(t, 75.0, v.(float64), 1e-9)
+
+	// a timestamp field is read whatever its format
+	created := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+	faker.SetRow(models.Row{
+		Lookup: func(string) (string, bool) { return "01/03/2024 18:00", true },
+		Value:  func(string) (models.Value, bool) { return models.TimestampValue(created, "02/01/2006 15:04"), true },
+	})
+	v, err = faker.Generate()
+	assert.NoError(t, err)
+	assert.InDelta(t, 75.0, v.(float64), 1e-9)
+
+	// text is parsed with event_format
+	faker, err = fakers.NewNumberFaker("", 0, 0, 100, nil, "linear:basis=event_time,event_field=created,event_format=02/01/2006T15:04,period=1d")
+	assert.NoError(t, err)
+	faker.SetRow(models.Row{Lookup: func(string) (string, bool) { return "01/03/2024T18:00", true }})
+	v, err = faker.Generate()
+	assert.NoError(t, err)
+	assert.InDelta(t, 75.0, v.(float64), 1e-9)
+
+	faker.SetRow(models.Row{Lookup: func(string) (string, bool) { return "", false }})
+	_, err = faker.Generate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `field "created" has not been generated yet`)
+	}
+}
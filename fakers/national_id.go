@@ -0,0 +1,158 @@
+package fakers
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
+)
+
+// generators for format- and checksum-valid identifiers. Only us_ssn is drawn from
+// a range that is never issued; the other schemes produce numbers that may
+// belong to real people.
+var nationalIDSchemes = map[string]func(rng *rand.Rand) string{
+	"uk_nino":  generateNINO,
+	"us_ssn":   generateSSN,
+	"es_dni":   generateDNI,
+	"es_nie":   generateNIE,
+	"nl_bsn":   generateBSN,
+	"fr_insee": generateINSEE,
+}
+
+type NationalIDFaker struct {
+	datatype models.Type
+	format   string
+	scheme   string
+	rng      *rand.Rand
+}
+
+func (f *NationalIDFaker) Generate() (any, error) {
+	return nationalIDSchemes[f.scheme](f.rng), nil
+}
+
+func (f *NationalIDFaker) GetType() models.Type { return f.datatype }
+func (f *NationalIDFaker) GetFormat() string    { return f.format }
+
+const (
+	ninoFirstLetters  = "ABCEGHJKLMNOPRSTWXYZ" // excludes D, F, I, Q, U, V
+	ninoSecondLetters = "ABCEGHJKLMNPRSTWXYZ"  // additionally excludes O
+	ninoSuffixes      = "ABCD"
+)
+
+var ninoInvalidPrefixes = map[string]struct{}{
+	"BG": {}, "GB": {}, "KN": {}, "NK": {}, "NT": {}, "TN": {}, "ZZ": {},
+}
+
+func generateNINO(rng *rand.Rand) string {
+	var prefix string
+	for {
+		prefix = string(ninoFirstLetters[rng.Intn(len(ninoFirstLetters))]) +
+			string(ninoSecondLetters[rng.Intn(len(ninoSecondLetters))])
+		if _, bad := ninoInvalidPrefixes[prefix]; !bad {
+			break
+		}
+	}
+	return fmt.Sprintf("%s%06d%c", prefix, rng.Intn(1000000), ninoSuffixes[rng.Intn(len(ninoSuffixes))])
+}
+
+// area numbers 900-999 are never issued as SSNs
+func generateSSN(rng *rand.Rand) string {
+	area := 900 + rng.Intn(100)
+	group := 1 + rng.Intn(99)
+	serial := 1 + rng.Intn(9999)
+	return fmt.Sprintf("%03d-%02d-%04d", area, group, serial)
+}
+
+const dniLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+func generateDNI(rng *rand.Rand) string {
+	n := rng.Intn(100000000)
+	return fmt.Sprintf("%08d%c", n, dniLetters[n%23])
+}
+
+// the NIE prefix X/Y/Z stands in for 0/1/2 when computing the check letter
+func generateNIE(rng *rand.Rand) string {
+	p := rng.Intn(3)
+	n := rng.Intn(10000000)
+	full := p*10000000 + n
+	return fmt.Sprintf("%c%07d%c", "XYZ"[p], n, dniLetters[full%23])
+}
+
+// BSN must pass the "11-test": 9*d1 + 8*d2 + ... + 2*d8 - d9 ≡ 0 (mod 11)
+func generateBSN(rng *rand.Rand) string {
+	for {
+		digits := make([]int, 9)
+		digits[0] = 1 + rng.Intn(9)
+		sum := 9 * digits[0]
+		for i := 1; i < 8; i++ {
+			digits[i] = rng.Intn(10)
+			sum += (9 - i) * digits[i]
+		}
+		check := sum % 11
+		if check == 10 {
+			continue
+		}
+		digits[8] = check
+
+		var b strings.Builder
+		for _, d := range digits {
+			b.WriteByte(byte('0' + d))
+		}
+		return b.String()
+	}
+}
+
+// INSEE/NIR: sex, year, month, department, commune, order number and a
+// two digit key of 97 - (first 13 digits mod 97). Corsican 2A/2B departments
+// are avoided so the number stays numeric.
+func generateINSEE(rng *rand.Rand) string {
+	sex := 1 + rng.Intn(2)
+	year := rng.Intn(100)
+	month := 1 + rng.Intn(12)
+	dept := 1 + rng.Intn(95)
+	if dept == 20 {
+		dept = 21
+	}
+	commune := 1 + rng.Intn(999)
+	order := 1 + rng.Intn(999)
+
+	body := fmt.Sprintf("%d%02d%02d%02d%03d%03d", sex, year, month, dept, commune, order)
+	n, _ := strconv.ParseInt(body, 10, 64)
+	key := 97 - n%97
+	return fmt.Sprintf("%s%02d", body, key)
+}
+
+func NewNationalIDFaker(format string, scheme string, rng *rand.Rand) (*NationalIDFaker, error) {
+	s := strings.ToLower(strings.TrimSpace(scheme))
+	if _, ok := nationalIDSchemes[s]; !ok {
+		return nil, fmt.Errorf("nationalid: unsupported scheme %q (expected uk_nino, us_ssn, es_dni, es_nie, nl_bsn or fr_insee)", scheme)
+	}
+	return &NationalIDFaker{
+		datatype: models.Type("NationalID"),
+		format:   format,
+		scheme:   s,
+		rng:      rng,
+	}, nil
+}
+
+func init() {
+	RegisterFaker("nationalid", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
+		faker, err := NewNationalIDFaker(field.Format, field.Scheme, rng)
+		if err != nil {
+			return nil, err
+		}
+		return faker, nil
+	})
+
+	DescribeFaker("nationalid", FakerInfo{
+		Description: "Checksum-valid national identifiers; only us_ssn uses a range that is never issued.",
+		Attributes: []Attribute{
+			{Name: "scheme", Type: "string", Description: "uk_nino | us_ssn | es_dni | es_nie | nl_bsn | fr_insee (required)"},
+			{Name: "format", Type: "string", Description: "ignored; scheme sets the layout"},
+		},
+		Examples: []string{`{ "name": "nino", "type": "nationalid", "scheme": "uk_nino" }`},
+	})
+}
@@ -0,0 +1,85 @@
+package fakers_test
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateIDs(t *testing.T, scheme string, n int) []string {
+	t.Helper()
+	faker, err := fakers.NewNationalIDFaker("", scheme, rand.New(rand.NewSource(7)))
+	assert.NoError(t, err)
+
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := faker.Generate()
+		assert.NoError(t, err)
+		out = append(out, v.(string))
+	}
+	return out
+}
+
+func TestNationalID_Formats(t *testing.T) {
+	tests := []struct {
+		scheme  string
+		pattern string
+	}{
+		{"uk_nino", `^[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z][0-9]{6}[A-D]$`},
+		{"us_ssn", `^9[0-9]{2}-[0-9]{2}-[0-9]{4}$`},
+		{"es_dni", `^[0-9]{8}[A-Z]$`},
+		{"es_nie", `^[XYZ][0-9]{7}[A-Z]$`},
+		{"nl_bsn", `^[1-9][0-9]{8}$`},
+		{"fr_insee", `^[12][0-9]{14}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			for _, id := range generateIDs(t, tt.scheme, 100) {
+				assert.Regexp(t, tt.pattern, id)
+			}
+		})
+	}
+}
+
+func TestNationalID_CheckDigits(t *testing.T) {
+	const letters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+	for _, id := range generateIDs(t, "es_dni", 50) {
+		n, _ := strconv.Atoi(id[:8])
+		assert.Equal(t, letters[n%23], id[8], id)
+	}
+
+	for _, id := range generateIDs(t, "es_nie", 50) {
+		n, _ := strconv.Atoi(strconv.Itoa(strings.IndexByte("XYZ", id[0])) + id[1:8])
+		assert.Equal(t, letters[n%23], id[8], id)
+	}
+
+	for _, id := range generateIDs(t, "nl_bsn", 50) {
+		sum := 0
+		for i := 0; i < 8; i++ {
+			sum += (9 - i) * int(id[i]-'0')
+		}
+		sum -= int(id[8] - '0')
+		assert.Zero(t, sum%11, id)
+	}
+
+	for _, id := range generateIDs(t, "fr_insee", 50) {
+		body, _ := strconv.ParseInt(id[:13], 10, 64)
+		key, _ := strconv.ParseInt(id[13:], 10, 64)
+		assert.Equal(t, 97-body%97, key, id)
+	}
+
+	for _, id := range generateIDs(t, "uk_nino", 200) {
+		assert.NotContains(t, []string{"BG", "GB", "KN", "NK", "NT", "TN", "ZZ"}, id[:2])
+	}
+}
+
+func TestNationalID_UnknownScheme(t *testing.T) {
+	_, err := fakers.NewNationalIDFaker("", "de_steuerid", rand.New(rand.NewSource(1)))
+	assert.Error(t, err)
+}
@@ -8,18 +8,26 @@ import (
 
 	"github.com/kream404/spoof/interfaces"
 	"github.com/kream404/spoof/models"
+	"github.com/shopspring/decimal"
 )
 
+const maxTruncatedDraws = 100
+
 type NumberFaker struct {
 	datatype models.Type
-	format   string  // decimal places (e.g. "2") or empty for raw float64
+	format   string  // decimal places (e.g. "2") for a decimal value, or empty for raw float64
 	length   int     // if set, produce a numeric string of this length
 	min      float64 // lower bound (inclusive)
 	max      float64 // upper bound (inclusive-ish)
 	rng      *rand.Rand
 	function string // e.g. "sin:period=86400", "random", "constant:value=42"
+	expr     *FunctionExpr
+	row      models.Row
 }
 
+// SetRow gives row-based function bases (basis=row, basis=event_time) their position.
+func (f *NumberFaker) SetRow(row models.Row) { f.row = row }
+
 func (f *NumberFaker) Generate() (any, error) {
 	if f.length != 0 {
 		if f.min != 0 || f.max != 0 {
@@ -48,10 +56,10 @@ func (f *NumberFaker) Generate() (any, error) {
 		return f.GenerateRandomNumberOfLength(f.length), nil
 	}
 
-	name, params := parseFunctionString(strings.TrimSpace(f.function))
+	params := f.expr.Params()
 
 	// Special case: constant:value as absolute numeric value
-	if name == "constant" {
+	if term, ok := f.expr.Single(); ok && term.Name == "constant" {
 		if v, ok := params["value"]; ok && v != "" {
 			if num, err := strconv.ParseFloat(v, 64); err == nil {
 				return f.formatValue(num)
@@ -59,8 +67,26 @@ func (f *NumberFaker) Generate() (any, error) {
 		}
 	}
 
-	norm := sampleNormalized(name, params, f.rng)
-	val := MapNormalizedToFloat(norm, params, f.min, f.max)
+	sample, err := f.expr.sample(f.rng, f.row)
+	if err != nil {
+		return nil, err
+	}
+
+	// absolute distributions are truncated to [min,max] by redrawing, so the
+	// tails don't pile up on the bounds; clamping is the last resort
+	if sample.Absolute && strings.ToLower(params["clamp"]) != "false" {
+		center := parseFloat(params["center"], 0.0)
+		for i := 0; i < maxTruncatedDraws; i++ {
+			if v := sample.Value + center; v >= f.min && v <= f.max {
+				break
+			}
+			if sample, err = f.expr.sample(f.rng, f.row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	val := MapNormalizedToFloat(sample, params, f.min, f.max)
 	return f.formatValue(val)
 }
 
@@ -72,8 +98,7 @@ func (f *NumberFaker) formatValue(val float64) (any, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid number format: %w", err)
 	}
-	format := fmt.Sprintf("%%.%df", decimals)
-	return fmt.Sprintf(format, val), nil
+	return models.DecimalValue(decimal.NewFromFloat(val), int32(decimals)), nil
 }
 
 func (f *NumberFaker) GenerateRandomNumberOfLength(length int) string {
@@ -106,6 +131,10 @@ func NewNumberFaker(format string, length int, min float64, max float64, rng *ra
 	if fn == "" {
 		fn = "random"
 	}
+	expr, err := parseFunctionString(fn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number config: %w", err)
+	}
 	return &NumberFaker{
 		datatype: models.Type("Number"),
 		format:   format,
@@ -114,15 +143,41 @@ func NewNumberFaker(format string, length int, min float64, max float64, rng *ra
 		max:      max,
 		rng:      rng,
 		function: fn,
+		expr:     expr,
 	}, nil
 }
 
 func init() {
 	RegisterFaker("number", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
-		faker, err := NewNumberFaker(field.Format, field.Length, field.Min, field.Max, rng, field.Function)
+		min, err := field.Min.Float()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number config: min %w", err)
+		}
+		max, err := field.Max.Float()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number config: max %w", err)
+		}
+		faker, err := NewNumberFaker(field.Format, field.Length, min, max, rng, field.Function)
 		if err != nil {
 			return nil, err
 		}
 		return faker, nil
 	})
+
+	DescribeFaker("number", FakerInfo{
+		Description: "A number in [min,max] shaped by a function, or a random numeric string of a fixed length.",
+		Attributes: []Attribute{
+			{Name: "min", Type: "number", Description: "lower bound"},
+			{Name: "max", Type: "number", Description: "upper bound"},
+			{Name: "format", Type: "int", Description: "decimal places; unset writes the raw value"},
+			{Name: "length", Type: "int", Description: "generate a numeric string of this many digits instead"},
+			{Name: "function", Type: "string", Default: "random", Description: "function string shaping the values"},
+		},
+		Functions: FunctionNames(),
+		Examples: []string{
+			`{ "name": "amount", "type": "number", "min": 1, "max": 250, "format": "2" }`,
+			`{ "name": "basket", "type": "number", "min": 0, "max": 500, "format": "2", "function": "normal:mean=120,stddev=40" }`,
+			`{ "name": "account", "type": "number", "length": 8 }`,
+		},
+	})
 }
@@ -44,4 +44,12 @@ func init() {
 	RegisterFaker("phone", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
 		return NewPhoneFaker(field.Format, rng), nil
 	})
+
+	DescribeFaker("phone", FakerInfo{
+		Description: "A phone number (placeholder value).",
+		Attributes: []Attribute{
+			{Name: "format", Type: "string", Description: "ignored; the number is a fixed placeholder"},
+		},
+		Examples: []string{`{ "name": "phone", "type": "phone" }`},
+	})
 }
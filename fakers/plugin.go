@@ -0,0 +1,209 @@
+package fakers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
+	log "github.com/kream404/spoof/services/logger"
+)
+
+// PluginBatchSize is how many values are requested from a plugin per call.
+const PluginBatchSize = 1000
+
+// DefaultPluginTimeout bounds a single plugin call.
+const DefaultPluginTimeout = 10 * time.Second
+
+// PluginRequest is written as JSON to the plugin's stdin.
+type PluginRequest struct {
+	Field models.Field `json:"field"`
+	Seed  int64        `json:"seed"`
+	Count int          `json:"count"`
+}
+
+// PluginResponse is read as JSON from the plugin's stdout. Values must hold
+// Count entries; a non-empty Error fails generation.
+type PluginResponse struct {
+	Values []any  `json:"values"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Plugin is an external executable that generates values for one faker type.
+type Plugin struct {
+	Name    string
+	Path    string
+	Timeout time.Duration
+}
+
+// PluginFaker is Stateful: one instance serves a field for a whole file and
+// hands out the values of each batch in turn.
+type PluginFaker struct {
+	datatype models.Type
+	format   string
+	plugin   Plugin
+	field    models.Field
+	rng      *rand.Rand
+
+	mu     sync.Mutex
+	row    models.Row
+	values []any
+	next   int
+}
+
+func (f *PluginFaker) Stateful() {}
+
+func (f *PluginFaker) SetRow(row models.Row) {
+	f.mu.Lock()
+	f.row = row
+	f.mu.Unlock()
+}
+
+func (f *PluginFaker) Generate() (any, error) {
+	f.mu.Lock()
+	if f.next < len(f.values) {
+		v := f.values[f.next]
+		f.next++
+		f.mu.Unlock()
+		return v, nil
+	}
+	seed := rand.Int63()
+	if f.rng != nil {
+		seed = f.rng.Int63()
+	}
+	req := PluginRequest{Field: f.field, Seed: seed, Count: f.batchSize()}
+	f.mu.Unlock()
+
+	values, err := f.plugin.Call(req)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values, f.next = values, 1
+	return values[0], nil
+}
+
+// batchSize is the rows still to generate, up to PluginBatchSize. A faker that
+// is not reused between rows only needs one value.
+func (f *PluginFaker) batchSize() int {
+	if f.row.Count <= 0 {
+		return 1
+	}
+	return max(1, min(PluginBatchSize, f.row.Count-f.row.Index+1))
+}
+
+func (f *PluginFaker) GetType() models.Type { return f.datatype }
+func (f *PluginFaker) GetFormat() string    { return f.format }
+
+func NewPluginFaker(plugin Plugin, field models.Field, rng *rand.Rand) (*PluginFaker, error) {
+	return &PluginFaker{
+		datatype: models.Type(plugin.Name),
+		format:   field.Format,
+		plugin:   plugin,
+		field:    field,
+		rng:      rng,
+	}, nil
+}
+
+// Call runs the plugin once for a batch of values.
+func (p Plugin) Call(req PluginRequest) ([]any, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(append(in, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second // don't hang on grandchildren holding stdout open
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("plugin %s: timed out after %s", p.Name, timeout)
+		}
+		return nil, fmt.Errorf("plugin %s: %w: %s", p.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp PluginResponse
+	dec := json.NewDecoder(&stdout)
+	dec.UseNumber() // keep numbers exactly as the plugin wrote them
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid response: %w", p.Name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.Name, resp.Error)
+	}
+	if len(resp.Values) != req.Count {
+		return nil, fmt.Errorf("plugin %s: expected %d values, got %d", p.Name, req.Count, len(resp.Values))
+	}
+	return resp.Values, nil
+}
+
+// PluginDir is where plugins are discovered by default: ~/.config/spoof/plugins.
+func PluginDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "spoof", "plugins")
+}
+
+// LoadPlugins registers every executable in dir as a faker named after the
+// file (without extension). Built-in fakers cannot be overridden. A missing
+// directory is not an error.
+func LoadPlugins(dir string, timeout time.Duration) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read plugin dir: %w", err)
+	}
+
+	var loaded []Plugin
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+
+		mu.Lock()
+		_, exists := registry[name]
+		mu.Unlock()
+		if exists {
+			log.Warn("Skipping plugin that shadows an existing faker", "plugin", e.Name(), "type", name)
+			continue
+		}
+
+		plugin := Plugin{Name: name, Path: filepath.Join(dir, e.Name()), Timeout: timeout}
+		RegisterFaker(name, func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
+			return NewPluginFaker(plugin, field, rng)
+		})
+		DescribeFaker(name, FakerInfo{
+			Description:   "External plugin " + plugin.Path,
+			AnyAttributes: true,
+		})
+		log.Debug("Registered plugin faker", "type", name, "path", plugin.Path)
+		loaded = append(loaded, plugin)
+	}
+	return loaded, nil
+}
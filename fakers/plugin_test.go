@@ -0,0 +1,88 @@
+package fakers_test
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/interfaces"
+	"github.com/kream404/spoof/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const refPlugin = `#!/bin/sh
+read req
+count=$(echo "$req" | sed 's/.*"count":\([0-9]*\).*/\1/')
+printf '{"values":['
+i=0
+while [ $i -lt $count ]; do
+  [ $i -gt 0 ] && printf ','
+  printf '"REF-%d/%d"' $i $count
+  i=$((i+1))
+done
+printf ']}'
+`
+
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755))
+}
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "ledgerref.sh", refPlugin)
+	writePlugin(t, dir, "broken", "#!/bin/sh\necho '{\"error\":\"no ledger configured\"}'\n")
+	writePlugin(t, dir, "slow", "#!/bin/sh\nexec sleep 5\n")
+	writePlugin(t, dir, "uuid", refPlugin)                                       // shadows a built-in
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644)) // not executable
+
+	plugins, err := fakers.LoadPlugins(dir, 200*time.Millisecond)
+	assert.NoError(t, err)
+	names := []string{}
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"ledgerref", "broken", "slow"}, names)
+
+	rng := rand.New(rand.NewSource(1))
+	field := models.Field{Name: "ref", Type: "ledgerref"}
+	factory, ok := fakers.GetFakerByName("ledgerref")
+	assert.True(t, ok)
+	faker, err := factory(field, rng)
+	assert.NoError(t, err)
+	assert.Implements(t, (*interfaces.Stateful)(nil), faker)
+	for i := 1; i <= 3; i++ {
+		// one faker serves the file, from a batch of the 3 rows it needs
+		faker.(interfaces.RowAware).SetRow(models.Row{Index: i, Count: 3})
+		v, err := faker.Generate()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("REF-%d/3", i-1), v)
+	}
+
+	// a faker used for one row only asks for one value
+	single, err := factory(field, rng)
+	assert.NoError(t, err)
+	v, err := single.Generate()
+	assert.NoError(t, err)
+	assert.Equal(t, "REF-0/1", v)
+
+	factory, _ = fakers.GetFakerByName("broken")
+	faker, _ = factory(models.Field{Name: "b", Type: "broken"}, rng)
+	_, err = faker.Generate()
+	assert.ErrorContains(t, err, "plugin broken: no ledger configured")
+
+	factory, _ = fakers.GetFakerByName("slow")
+	faker, _ = factory(models.Field{Name: "s", Type: "slow"}, rng)
+	start := time.Now()
+	_, err = faker.Generate()
+	assert.ErrorContains(t, err, "plugin slow: timed out")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
@@ -63,4 +63,13 @@ func init() {
 		}
 		return faker, nil
 	})
+
+	DescribeFaker("range", FakerInfo{
+		Description: "One of a fixed list of values, picked at random.",
+		Attributes: []Attribute{
+			{Name: "values", Type: "list", Description: "comma separated values (required)"},
+			{Name: "format", Type: "string", Description: "ignored; values are written as listed"},
+		},
+		Examples: []string{`{ "name": "status", "type": "range", "values": "active, closed, frozen" }`},
+	})
 }
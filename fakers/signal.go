@@ -4,60 +4,22 @@ import (
 	"math"
 	"math/rand"
 	"strings"
-	"time"
 )
 
-func parseFunctionString(s string) (string, map[string]string) {
-	s = strings.TrimSpace(s)
-	params := make(map[string]string)
-
-	if s == "" {
-		return "", params
-	}
-
-	name := s
-	rest := ""
-	if i := strings.Index(s, ":"); i >= 0 {
-		name = strings.TrimSpace(s[:i])
-		rest = strings.TrimSpace(s[i+1:])
-	} else {
-		name = strings.TrimSpace(s)
-	}
-
-	if rest != "" {
-		rest = strings.ReplaceAll(rest, ";", ",")
-
-		for _, part := range strings.Split(rest, ",") {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-			kv := strings.SplitN(part, "=", 2)
-			if len(kv) != 2 {
-				continue
-			}
-			k := strings.ToLower(strings.TrimSpace(kv[0]))
-			v := strings.TrimSpace(kv[1])
-			if k != "" {
-				params[k] = v
-			}
-		}
-	}
-
-	return strings.ToLower(name), params
-}
-
 // sampleNormalized returns a value in [0,1] for the provided function name and params.
 // rng may be nil: fallback to math/rand.
 //
 // Period param accepts either a plain numeric value in seconds (e.g. "60")
 // OR a duration string supported by ParseDurationExt (e.g. "7d", "72h", "1.5d").
 //
+// t is the position sin/linear are evaluated at (see functionTime); with the
+// default wall-clock basis it is the current unix time in seconds.
+//
 // Jitter params:
 //   - "jitter" (probability 0..1) enables occasional outliers
 //   - "jitter_type" in {"scale","edge","spike"} controls how outliers are created
 //   - "jitter_amp" multiplier used for "scale" type (default 3.0)
-func sampleNormalized(fn string, params map[string]string, rng *rand.Rand) float64 {
+func sampleNormalized(fn string, params map[string]string, rng *rand.Rand, t float64) float64 {
 	var base float64
 
 	switch fn {
@@ -85,7 +47,6 @@ func sampleNormalized(fn string, params map[string]string, rng *rand.Rand) float
 		}
 
 		phaseDeg := parseFloat(params["phase"], 0.0)
-		t := float64(time.Now().UnixNano()) / 1e9
 		phase := 2*math.Pi*(t/period) + (phaseDeg * math.Pi / 180.0)
 		s := math.Sin(phase)   // [-1,1]
 		base = (s + 1.0) / 2.0 // [0,1]
@@ -104,8 +65,10 @@ func sampleNormalized(fn string, params map[string]string, rng *rand.Rand) float
 		if period <= 0 {
 			period = 60.0
 		}
-		t := float64(time.Now().UnixNano()) / 1e9
 		base = math.Mod(t, period) / period // [0,1)
+		if base < 0 {
+			base += 1 // event times before the epoch
+		}
 
 	case "constant":
 		if v, ok := params["valuenorm"]; ok && v != "" {
@@ -176,6 +139,14 @@ func sampleNormalized(fn string, params map[string]string, rng *rand.Rand) float
 			base = highVal
 		}
 
+	case "beta":
+		// params: a, b shape parameters (default 2, 2)
+		base = sampleBeta(params, rng)
+
+	case "triangular":
+		// params: mode, the peak as a fraction of the range (default 0.5)
+		base = sampleTriangular(params, rng)
+
 	default:
 		// unknown -> fallback to constant 0
 		base = 0.0
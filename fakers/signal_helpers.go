@@ -32,14 +32,24 @@ func parseAmplitude(params map[string]string) float64 {
 //   - center shift (param "center", parsed as float, default 0.0)
 //   - optional clamping controlled by param "clamp" (default "true", set "false" to disable)
 //
+// Absolute samples (normal, poisson, ...) skip the range mapping and are only shifted by center and clamped.
 // The returned value is not rounded/formatted by this helper (formating is up to the caller).
-func MapNormalizedToFloat(norm float64, params map[string]string, min, max float64) float64 {
+func MapNormalizedToFloat(sample Sample, params map[string]string, min, max float64) float64 {
 	if min > max {
 		min, max = max, min
 	}
 	amp := parseAmplitude(params)
 	center := parseFloat(params["center"], 0.0)
 
+	if sample.Absolute {
+		val := sample.Value + center
+		if strings.ToLower(params["clamp"]) != "false" && min != max {
+			val = math.Max(min, math.Min(max, val))
+		}
+		return val
+	}
+	norm := sample.Value
+
 	// midpoint and half-range
 	mid := (min + max) / 2.0
 	half := (max - min) / 2.0 * amp
@@ -59,13 +69,21 @@ func MapNormalizedToFloat(norm float64, params map[string]string, min, max float
 	return val
 }
 
+// sampleUnit is the duration one unit of an absolute sample represents for
+// timestamps (param "unit", default 1s), e.g. "lognormal:mu=1,unit=1d".
+func sampleUnit(params map[string]string) time.Duration {
+	return ParseDurationExt(params["unit"], time.Second)
+}
+
 // --------- Duration mapping (for TimestampFaker etc.) ---------
 // MapNormalizedToDuration maps a normalized sample (0..1) into a time.Duration offset.
+// Absolute samples are read as a count of params["unit"] instead of a position in the interval.
 // Params:
 //   - params["amplitude"] (multiplier, default 1.0)
 //   - params["center"] (duration string, e.g. "1d" or "-2h", optional)
 //   - dir: "past" | "future" | "both" (affects sign mapping)
-func MapNormalizedToDuration(norm float64, params map[string]string, base time.Duration, dir string) time.Duration {
+func MapNormalizedToDuration(sample Sample, params map[string]string, base time.Duration, dir string) time.Duration {
+	norm := sample.Value
 
 	amp := parseAmplitude(params)
 
@@ -93,6 +111,14 @@ func MapNormalizedToDuration(norm float64, params map[string]string, base time.D
 
 	var result time.Duration
 
+	if sample.Absolute {
+		offset := time.Duration(sample.Value * float64(sampleUnit(params)))
+		if d == "past" {
+			offset = -offset
+		}
+		return offset + center
+	}
+
 	switch d {
 	case "past":
 		// [-effective, 0]
@@ -109,3 +135,26 @@ func MapNormalizedToDuration(norm float64, params map[string]string, base time.D
 
 	return result
 }
+
+// --------- Absolute time mapping (for bounded TimestampFaker) ---------
+// MapNormalizedToTime maps a normalized sample (0..1) into the window [min,max].
+// Absolute samples are read as a count of params["unit"] after min.
+// Values outside the window (e.g. from jitter) are clamped unless params["clamp"] is "false".
+func MapNormalizedToTime(sample Sample, params map[string]string, min, max time.Time) time.Time {
+	clamp := strings.ToLower(params["clamp"]) != "false"
+	if sample.Absolute {
+		t := min.Add(time.Duration(sample.Value * float64(sampleUnit(params))))
+		if clamp && t.After(max) {
+			return max
+		}
+		if clamp && t.Before(min) {
+			return min
+		}
+		return t
+	}
+	norm := sample.Value
+	if clamp {
+		norm = math.Max(0, math.Min(1, norm))
+	}
+	return min.Add(time.Duration(norm * float64(max.Sub(min))))
+}
@@ -1,6 +1,7 @@
 package fakers
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -16,13 +17,28 @@ type TimestampFaker struct {
 	interval time.Duration // default magnitude for offsets (can be negative to imply past)
 	rng      *rand.Rand
 	function string // e.g. "sin:period=7d,dir=both,amplitude=2,center=-1d"
+	expr     *FunctionExpr
+	location *time.Location
+	row      models.Row
+
+	// absolute window; when set the function is mapped across [min,max] instead of offsets from now
+	bounded bool
+	min     time.Time
+	max     time.Time
 }
 
 func (f *TimestampFaker) Generate() (any, error) {
-	now := time.Now().UTC().Truncate(time.Second)
+	now := time.Now().In(f.location).Truncate(time.Second)
 
-	// parse function string
-	name, params := parseFunctionString(strings.TrimSpace(f.function))
+	params := f.expr.Params()
+
+	if f.bounded {
+		sample, err := f.expr.sample(f.rng, f.row)
+		if err != nil {
+			return nil, err
+		}
+		return formatTime(MapNormalizedToTime(sample, params, f.min, f.max), f.format), nil
+	}
 
 	// per-call interval override (supports "7d", "72h", "3600s", etc.)
 	useInterval := f.interval
@@ -48,7 +64,7 @@ func (f *TimestampFaker) Generate() (any, error) {
 		}
 	}
 
-	if name == "constant" {
+	if term, ok := f.expr.Single(); ok && term.Name == "constant" {
 		if v := params["value"]; v != "" {
 			// allow negative leading sign too
 			sign := 1.0
@@ -73,8 +89,11 @@ func (f *TimestampFaker) Generate() (any, error) {
 		}
 	}
 
-	norm := sampleNormalized(name, params, f.rng)
-	offset := MapNormalizedToDuration(norm, params, useInterval, dir)
+	sample, err := f.expr.sample(f.rng, f.row)
+	if err != nil {
+		return nil, err
+	}
+	offset := MapNormalizedToDuration(sample, params, useInterval, dir)
 
 	value := now.Add(offset)
 	return formatTime(value, f.format), nil
@@ -82,30 +101,87 @@ func (f *TimestampFaker) Generate() (any, error) {
 
 func formatTime(t time.Time, format string) any {
 	if format != "" {
-		return t.Format(format)
+		return models.TimestampValue(t, format)
 	}
 	return t
 }
 
+// SetRow gives row-based function bases (basis=row, basis=event_time) their position.
+func (f *TimestampFaker) SetRow(row models.Row) { f.row = row }
+
 func (f *TimestampFaker) GetType() models.Type { return f.datatype }
 func (f *TimestampFaker) GetFormat() string    { return f.format }
 
-func NewTimestampFaker(format string, intervalSeconds int64, rng *rand.Rand, function string) *TimestampFaker {
-	fn := strings.TrimSpace(function)
-	if fn == "" {
-		fn = "constant"
+// min/max are optional absolute bounds or date expressions (see ParseDateExpr); both or neither must be set.
+func NewTimestampFaker(format string, intervalSeconds int64, rng *rand.Rand, function string, min string, max string, timezone string) (*TimestampFaker, error) {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
 	}
-	return &TimestampFaker{
+
+	fn := strings.TrimSpace(function)
+	f := &TimestampFaker{
 		datatype: models.Type("Timestamp"),
 		format:   format,
 		interval: time.Duration(intervalSeconds) * time.Second,
 		rng:      rng,
-		function: fn,
+		location: loc,
 	}
+
+	if min != "" || max != "" {
+		if min == "" || max == "" {
+			return nil, fmt.Errorf("invalid timestamp config: both min and max must be set for a date range")
+		}
+		now := time.Now()
+		if f.min, err = ParseDateExpr(min, format, loc, now); err != nil {
+			return nil, fmt.Errorf("invalid timestamp config: min: %w", err)
+		}
+		if f.max, err = ParseDateExpr(max, format, loc, now); err != nil {
+			return nil, fmt.Errorf("invalid timestamp config: max: %w", err)
+		}
+		if f.max.Before(f.min) {
+			return nil, fmt.Errorf("invalid timestamp config: min must be <= max")
+		}
+		f.bounded = true
+		// a fixed window has nothing to be constant relative to, so spread values across it
+		if fn == "" {
+			fn = "random"
+		}
+	}
+
+	if fn == "" {
+		fn = "constant"
+	}
+	if f.expr, err = parseFunctionString(fn); err != nil {
+		return nil, fmt.Errorf("invalid timestamp config: %w", err)
+	}
+	f.function = fn
+	return f, nil
 }
 
 func init() {
 	RegisterFaker("timestamp", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
-		return NewTimestampFaker(field.Format, field.Interval, rng, field.Function), nil
+		faker, err := NewTimestampFaker(field.Format, field.Interval, rng, field.Function, string(field.Min), string(field.Max), field.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		return faker, nil
+	})
+
+	DescribeFaker("timestamp", FakerInfo{
+		Description: "A timestamp offset from now, or within a [min,max] window, shaped by a function.",
+		Attributes: []Attribute{
+			{Name: "format", Type: "string", Description: "Go time layout, e.g. 2006-01-02; unset writes the full time"},
+			{Name: "function", Type: "string", Default: "constant (random with min/max)", Description: "function string shaping the values"},
+			{Name: "interval", Type: "int", Description: "default offset magnitude in seconds; negative implies the past"},
+			{Name: "min", Type: "date", Description: "start of the window: a date or expression such as today-30d"},
+			{Name: "max", Type: "date", Description: "end of the window"},
+			{Name: "timezone", Type: "string", Default: "UTC", Description: "IANA time zone, e.g. Europe/London"},
+		},
+		Functions: FunctionNames(),
+		Examples: []string{
+			`{ "name": "created_at", "type": "timestamp", "format": "2006-01-02", "function": "random:dir=past,interval=7d" }`,
+			`{ "name": "booked_at", "type": "timestamp", "min": "start_of_month", "max": "end_of_month", "timezone": "Europe/London" }`,
+		},
 	})
 }
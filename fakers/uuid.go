@@ -43,4 +43,12 @@ func init() {
 	RegisterFaker("uuid", func(field models.Field, rng *rand.Rand) (interfaces.Faker[any], error) {
 		return NewUUIDFaker(field.Format, rng), nil
 	})
+
+	DescribeFaker("uuid", FakerInfo{
+		Description: "A version 7 UUID.",
+		Attributes: []Attribute{
+			{Name: "format", Type: "string", Description: "ignored; UUIDs are always in the hyphenated 8-4-4-4-12 form"},
+		},
+		Examples: []string{`{ "name": "id", "type": "uuid" }`},
+	})
 }
@@ -12,6 +12,17 @@ type Faker[T any] interface {
 	GetFormat() string
 }
 
+// RowAware fakers are told which row they are generating for before Generate is called
+type RowAware interface {
+	SetRow(row models.Row)
+}
+
+// Stateful fakers are created once per file and field and reused for every row,
+// so state such as a buffered batch carries over between rows
+type Stateful interface {
+	Stateful()
+}
+
 func GetType() models.Type{
 	return datatype
 }
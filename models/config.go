@@ -1,32 +1,80 @@
 package models
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
 
 type Config struct {
-	FileName       string `json:"file_name"`
-	Delimiter      string `json:"delimiter"`
-	RowCount       int    `json:"row_count,string"` // <-- allow quoted numbers
-	FileCount      int    `json:"file_count,omitempty,string"`
-	IncludeHeaders bool   `json:"include_headers"`
-	Header         string `json:"header,omitempty"`
-	Footer         string `json:"footer,omitempty"`
-	Seed           string `json:"seed,omitempty"`
+	FileName       string          `json:"file_name"`
+	Delimiter      string          `json:"delimiter"`
+	Format         string          `json:"format,omitempty"` // csv (default) | jsonl | parquet | avro | sql | fixed | xml | xlsx
+	RowCount       int             `json:"row_count,string"` // <-- allow quoted numbers
+	FileCount      int             `json:"file_count,omitempty,string"`
+	IncludeHeaders bool            `json:"include_headers"`
+	Header         string          `json:"header,omitempty"`
+	Footer         string          `json:"footer,omitempty"`
+	Seed           string          `json:"seed,omitempty"`
+	BatchSize      int             `json:"batch_size,omitempty,string"` // detail records per batch, with records
+	Sheet          string          `json:"sheet,omitempty"`             // xlsx sheet; entities with the same file_name share a workbook
+	Compression    string          `json:"compression,omitempty"`       // gzip | zstd; adds .gz or .zst to file_name
+	Parquet        *ParquetOptions `json:"parquet,omitempty"`
+	Avro           *AvroOptions    `json:"avro,omitempty"`
+	SQL            *SQLOptions     `json:"sql,omitempty"`
+	XML            *XMLOptions     `json:"xml,omitempty"`
+}
+
+type ParquetOptions struct {
+	RowGroupSize int    `json:"row_group_size,omitempty,string"` // rows per row group
+	Compression  string `json:"compression,omitempty"`           // none | snappy (default) | gzip | zstd
+}
+
+type AvroOptions struct {
+	Codec        string `json:"codec,omitempty"`     // null (default) | deflate | snappy
+	Namespace    string `json:"namespace,omitempty"` // default "spoof"
+	Record       string `json:"record,omitempty"`    // record name; default the file name
+	ExportSchema bool   `json:"export_schema,omitempty"`
+}
+
+// SQLOptions configures the sql format; the table comes from postprocess.schema/table.
+type SQLOptions struct {
+	Dialect   string `json:"dialect,omitempty"`      // postgres (default) | mysql | sqlite
+	Mode      string `json:"mode,omitempty"`         // insert (default) | copy (postgres only)
+	BatchSize int    `json:"batch,omitempty,string"` // rows per INSERT; default postprocess.batch, then 500
+}
+
+// XMLOptions configures the xml format. Both are template file paths.
+type XMLOptions struct {
+	Template string `json:"template"`           // one row, with ${field} placeholders
+	Envelope string `json:"envelope,omitempty"` // the document around ${rows}; default <rows>...</rows>
 }
 
 type Postprocess struct {
-	Enabled   bool     `json:"enabled,omitempty"`
-	Operation string   `json:"operation,omitempty"`
-	Location  string   `json:"location,omitempty"`
-	Region    string   `json:"region,omitempty"`
-	Schema    string   `json:"schema,omitempty"`
-	Table     string   `json:"table,omitempty"`
-	Key       string   `json:"key,omitempty"`
-	Alias     string   `json:"alias,omitempty"`
-	Type      string   `json:"type,omitempty"`
-	HasHeader bool     `json:"headers,omitempty"`
-	TrimSpace bool     `json:"trim,omitempty"`
-	Columns   []string `json:"columns,omitempty"`
-	BatchSize int      `json:"batch,string,omitempty"` // already string-coerced
+	Enabled   bool            `json:"enabled,omitempty"`
+	Operation string          `json:"operation,omitempty"`
+	Location  string          `json:"location,omitempty"`
+	Region    string          `json:"region,omitempty"`
+	Schema    string          `json:"schema,omitempty"`
+	Table     string          `json:"table,omitempty"`
+	Key       string          `json:"key,omitempty"`
+	Alias     string          `json:"alias,omitempty"`
+	Type      string          `json:"type,omitempty"`
+	HasHeader bool            `json:"headers,omitempty"`
+	TrimSpace bool            `json:"trim,omitempty"`
+	Columns   []string        `json:"columns,omitempty"`
+	BatchSize int             `json:"batch,string,omitempty"` // already string-coerced
+	Encrypt   *EncryptOptions `json:"encrypt,omitempty"`
+}
+
+// EncryptOptions PGP-encrypts the generated file before it is uploaded.
+type EncryptOptions struct {
+	Recipients []string `json:"recipients"`           // public key files, ASCII-armored or binary
+	Armor      bool     `json:"armor,omitempty"`      // write .asc instead of binary .gpg
+	SignKey    string   `json:"sign_key,omitempty"`   // private key file to sign with
+	Passphrase string   `json:"passphrase,omitempty"` // for sign_key
 }
 
 type Profiles struct {
@@ -59,31 +107,41 @@ type SeedSelector struct {
 }
 
 type Field struct {
-	Name       string  `json:"name"`
-	Alias      string  `json:"alias,omitempty"`
-	Type       string  `json:"type,omitempty"`
-	Modifier   string  `json:"modifier,omitempty"`
-	AutoInc    bool    `json:"auto_increment,omitempty"`
-	ForeignKey string  `json:"foreign_key,omitempty"`
-	Format     string  `json:"format,omitempty"`
-	Length     int     `json:"length,omitempty"`
-	Min        float64 `json:"min,omitempty"`
-	Max        float64 `json:"max,omitempty"`
-	Start      *int    `json:"start,omitempty"`
-	Value      string  `json:"value,omitempty"`
-	Values     string  `json:"values,omitempty"`
-	Interval   int64   `json:"interval,omitempty"`
-	Target     string  `json:"target,omitempty"`
-	Seed       bool    `json:"seed,omitempty"`
-	Selector   bool    `json:"selector,omitempty"`
-	Function   string  `json:"function,omitempty"`
-	Source     string  `json:"source,omitempty"`
-	Template   string  `json:"template,omitempty"`
-	Rate       *int    `json:"rate,omitempty,string"`
-	Regex      string  `json:"regex,omitempty"`
-	Fields     []Field `json:"fields,omitempty"`
-	Repeat     int     `json:"repeat,omitempty"`
-	Skip       bool    `json:"skip,omitempty"`
+	Name        string   `json:"name"`
+	Alias       string   `json:"alias,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Modifier    string   `json:"modifier,omitempty"`
+	AutoInc     bool     `json:"auto_increment,omitempty"`
+	ForeignKey  string   `json:"foreign_key,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Scheme      string   `json:"scheme,omitempty"`
+	Length      int      `json:"length,omitempty"`
+	Min         Bound    `json:"min,omitempty"`
+	Max         Bound    `json:"max,omitempty"`
+	Start       *int     `json:"start,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Values      string   `json:"values,omitempty"`
+	Interval    int64    `json:"interval,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"`
+	Target      string   `json:"target,omitempty"`
+	Seed        bool     `json:"seed,omitempty"`
+	Selector    bool     `json:"selector,omitempty"`
+	Function    string   `json:"function,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	Template    string   `json:"template,omitempty"`
+	Script      string   `json:"script,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Rate        *int     `json:"rate,omitempty,string"`
+	NullRate    *int     `json:"null_rate,omitempty,string"` // percent of rows that are null
+	Probability *float64 `json:"probability,omitempty"`
+	Regex       string   `json:"regex,omitempty"`
+	Fields      []Field  `json:"fields,omitempty"`
+	Repeat      int      `json:"repeat,omitempty"`
+	Skip        bool     `json:"skip,omitempty"`
+	Width       int      `json:"width,omitempty"`    // fixed format column width
+	Align       string   `json:"align,omitempty"`    // left (default) | right
+	PadChar     string   `json:"pad_char,omitempty"` // default " "
+	Truncate    string   `json:"truncate,omitempty"` // right (default) | left | error
 }
 
 type Entity struct {
@@ -93,6 +151,15 @@ type Entity struct {
 	Fields      []Field             `json:"fields"`
 	Source      string              `json:"source,omitempty"`
 	Output      []map[string]string `json:"output,omitempty"`
+	Records     []Record            `json:"records,omitempty"`
+}
+
+// Record is a header or trailer record type in a multi-record file. The detail
+// records between them are the entity's own fields.
+type Record struct {
+	Name   string  `json:"name"`
+	Kind   string  `json:"kind"` // header | batch_header | batch_trailer | trailer
+	Fields []Field `json:"fields"`
 }
 
 type FileConfig struct {
@@ -117,6 +184,51 @@ func (p Placeholder) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s)
 }
 
+// Bound holds a min/max attribute. It is a number for numeric fakers, but
+// timestamp fields also accept dates and date expressions ("today-30d").
+type Bound string
+
+func (b *Bound) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Bound(strings.TrimSpace(s))
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("min/max must be a number or string: %s", data)
+	}
+	*b = Bound(n.String())
+	return nil
+}
+
+// jsonNumber is the JSON number grammar. ParseFloat also accepts NaN, Inf,
+// hex floats and underscores, none of which can be written unquoted.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// MarshalJSON writes a finite decimal bound as a number and anything else as
+// a string.
+func (b Bound) MarshalJSON() ([]byte, error) {
+	if jsonNumber.MatchString(string(b)) {
+		if _, err := strconv.ParseFloat(string(b), 64); err == nil {
+			return []byte(b), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// Float returns the bound as a number; an unset bound is 0.
+func (b Bound) Float() (float64, error) {
+	if b == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", string(b))
+	}
+	return f, nil
+}
+
 type BundleFile struct {
 	Source string `json:"source"`
 }
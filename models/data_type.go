@@ -14,3 +14,13 @@ const (
 func GetType(t Type) Type {
 	return t
 }
+
+// Row describes the row being generated. Lookup returns a value already
+// generated earlier in the row (or in the parent row for nested fields), and
+// Value the same value with its type, when it was generated in this row.
+type Row struct {
+	Index  int
+	Count  int // rows in the file, or 0 when the faker is not reused between rows
+	Lookup func(name string) (string, bool)
+	Value  func(name string) (Value, bool)
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kind is the type of a generated value.
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindString
+	KindInt
+	KindFloat
+	KindDecimal
+	KindBool
+	KindTimestamp
+	KindJSON
+)
+
+var kindNames = [...]string{"null", "string", "int", "float", "decimal", "bool", "timestamp", "json"}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Value is a typed cell value. Text formats (csv, templates) write Text; typed
+// sinks (json, parquet, databases) read the payload for the Kind:
+//
+//	KindString, KindJSON  Str
+//	KindInt               Int
+//	KindFloat             Float
+//	KindDecimal           Decimal, rounded to Places
+//	KindBool              Bool
+//	KindTimestamp         Time, with Layout the field's format
+type Value struct {
+	Kind    Kind
+	Str     string
+	Int     int64
+	Float   float64
+	Decimal decimal.Decimal
+	Places  int32
+	Bool    bool
+	Time    time.Time
+	Layout  string
+	Text    string
+}
+
+func (v Value) String() string { return v.Text }
+func (v Value) IsNull() bool   { return v.Kind == KindNull }
+
+// Any returns the payload as a plain Go value: nil, string, int64, float64,
+// decimal.Decimal, bool, time.Time or json.RawMessage.
+func (v Value) Any() any {
+	switch v.Kind {
+	case KindString:
+		return v.Str
+	case KindInt:
+		return v.Int
+	case KindFloat:
+		return v.Float
+	case KindDecimal:
+		return v.Decimal
+	case KindBool:
+		return v.Bool
+	case KindTimestamp:
+		return v.Time
+	case KindJSON:
+		return json.RawMessage(v.Str)
+	default:
+		return nil
+	}
+}
+
+func NullValue() Value { return Value{Kind: KindNull} }
+
+func StringValue(s string) Value { return Value{Kind: KindString, Str: s, Text: s} }
+
+func IntValue(i int64) Value { return Value{Kind: KindInt, Int: i, Text: strconv.FormatInt(i, 10)} }
+
+func FloatValue(f float64) Value {
+	return Value{Kind: KindFloat, Float: f, Text: strconv.FormatFloat(f, 'f', -1, 64)}
+}
+
+// DecimalValue rounds d to places decimal places, which are always written (12.50, not 12.5).
+func DecimalValue(d decimal.Decimal, places int32) Value {
+	d = d.Round(places)
+	return Value{Kind: KindDecimal, Decimal: d, Places: places, Text: d.StringFixed(places)}
+}
+
+func BoolValue(b bool) Value { return Value{Kind: KindBool, Bool: b, Text: strconv.FormatBool(b)} }
+
+// BoolText is a boolean written to text formats with custom tokens, e.g. Y/N.
+func BoolText(b bool, text string) Value {
+	return Value{Kind: KindBool, Bool: b, Text: text}
+}
+
+// TimestampValue formats t with layout for text formats; an empty layout is RFC3339.
+func TimestampValue(t time.Time, layout string) Value {
+	text := layout
+	if layout == "" {
+		text = time.RFC3339Nano
+	}
+	return Value{Kind: KindTimestamp, Time: t, Layout: layout, Text: t.Format(text)}
+}
+
+func JSONValue(raw string) Value { return Value{Kind: KindJSON, Str: raw, Text: raw} }
+
+// ValueOf wraps a faker's result, keeping its type.
+func ValueOf(v any) Value {
+	switch x := v.(type) {
+	case nil:
+		return NullValue()
+	case Value:
+		return x
+	case string:
+		return StringValue(x)
+	case []byte:
+		return StringValue(string(x))
+	case bool:
+		return BoolValue(x)
+	case int:
+		return IntValue(int64(x))
+	case int32:
+		return IntValue(int64(x))
+	case int64:
+		return IntValue(x)
+	case uint32:
+		return IntValue(int64(x))
+	case float32:
+		return FloatValue(float64(x))
+	case float64:
+		return FloatValue(x)
+	case decimal.Decimal:
+		return Value{Kind: KindDecimal, Decimal: x, Places: -x.Exponent(), Text: x.String()}
+	case time.Time:
+		return TimestampValue(x, "")
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return IntValue(i)
+		}
+		if f, err := x.Float64(); err == nil {
+			return FloatValue(f)
+		}
+		return StringValue(x.String())
+	case json.RawMessage:
+		return JSONValue(string(x))
+	case fmt.Stringer:
+		return StringValue(x.String())
+	default:
+		return StringValue(fmt.Sprint(x))
+	}
+}
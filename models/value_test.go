@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kream404/spoof/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueOf(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   any
+		kind models.Kind
+		text string
+	}{
+		{"nil", nil, models.KindNull, ""},
+		{"string", "abc", models.KindString, "abc"},
+		{"int", 42, models.KindInt, "42"},
+		{"int64", int64(-7), models.KindInt, "-7"},
+		{"float", 12.5, models.KindFloat, "12.5"},
+		{"bool", true, models.KindBool, "true"},
+		{"time", ts, models.KindTimestamp, "2024-03-01T09:30:00Z"},
+		{"json number int", json.Number("10"), models.KindInt, "10"},
+		{"json number float", json.Number("1.25"), models.KindFloat, "1.25"},
+		{"decimal", decimal.RequireFromString("3.10"), models.KindDecimal, "3.1"},
+		{"value", models.BoolText(false, "N"), models.KindBool, "N"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := models.ValueOf(tt.in)
+			assert.Equal(t, tt.kind, v.Kind)
+			assert.Equal(t, tt.text, v.String())
+		})
+	}
+}
+
+func TestValueFormatting(t *testing.T) {
+	d := models.DecimalValue(decimal.NewFromFloat(12.5), 2)
+	assert.Equal(t, "12.50", d.String())
+	assert.True(t, d.Decimal.Equal(decimal.RequireFromString("12.5")))
+
+	ts := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	v := models.TimestampValue(ts, "02/01/2006")
+	assert.Equal(t, "01/03/2024", v.String())
+	assert.Equal(t, ts, v.Any())
+
+	assert.Equal(t, json.RawMessage(`{"a":1}`), models.JSONValue(`{"a":1}`).Any())
+	assert.True(t, models.NullValue().IsNull())
+}
+
+func TestBoundMarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   models.Bound
+		want string
+	}{
+		{"12.5", `12.5`},
+		{"-3", `-3`},
+		{"1e6", `1e6`},
+		{"NaN", `"NaN"`},
+		{"Inf", `"Inf"`},
+		{"-infinity", `"-infinity"`},
+		{"0x1p-2", `"0x1p-2"`},
+		{"1e400", `"1e400"`},
+		{"+5", `"+5"`},
+		{"today-30d", `"today-30d"`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.in), func(t *testing.T) {
+			got, err := json.Marshal(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.True(t, json.Valid(got))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codecs for config.compression. bzip2 is read only.
+const (
+	None  = ""
+	Gzip  = "gzip"
+	Zstd  = "zstd"
+	Bzip2 = "bzip2"
+)
+
+var extensions = map[string]string{
+	Gzip:  ".gz",
+	Zstd:  ".zst",
+	Bzip2: ".bz2",
+}
+
+// Parse normalises a config.compression value; "none" and "" are None.
+func Parse(codec string) (string, error) {
+	c := strings.ToLower(strings.TrimSpace(codec))
+	switch c {
+	case "", "none":
+		return None, nil
+	case "gz":
+		return Gzip, nil
+	case "zst":
+		return Zstd, nil
+	case Gzip, Zstd:
+		return c, nil
+	case Bzip2, "bz2":
+		return "", fmt.Errorf("bzip2 can be read but not written (expected gzip or zstd)")
+	default:
+		return "", fmt.Errorf("unknown compression %q (expected gzip or zstd)", codec)
+	}
+}
+
+// Ext is the file extension for codec, e.g. ".gz", or "" for None.
+func Ext(codec string) string {
+	return extensions[codec]
+}
+
+// WithExt appends codec's extension to name unless it already ends with it.
+func WithExt(name, codec string) string {
+	ext := Ext(codec)
+	if ext == "" || strings.HasSuffix(strings.ToLower(name), ext) {
+		return name
+	}
+	return name + ext
+}
+
+// TrimExt removes a compression extension from name: data.csv.gz is data.csv.
+func TrimExt(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, ext) {
+			return name[:len(name)-len(ext)]
+		}
+	}
+	return name
+}
+
+// NewWriter compresses what is written to w with codec. Close flushes the
+// compressed stream but does not close w.
+func NewWriter(codec string, w io.Writer) (io.WriteCloser, error) {
+	c, err := Parse(codec)
+	if err != nil {
+		return nil, err
+	}
+	switch c {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	default:
+		return nopCloser{w}, nil
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+var magic = []struct {
+	codec  string
+	prefix []byte
+}{
+	{Gzip, []byte{0x1f, 0x8b}},
+	{Zstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{Bzip2, []byte("BZh")},
+}
+
+// NewReader decompresses r when it starts with a gzip, zstd or bzip2 header,
+// and otherwise reads it as is.
+func NewReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(4)
+
+	for _, m := range magic {
+		if !bytes.HasPrefix(head, m.prefix) {
+			continue
+		}
+		switch m.codec {
+		case Gzip:
+			return gzip.NewReader(br)
+		case Zstd:
+			d, err := zstd.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		case Bzip2:
+			// BZh is followed by the block size, 1-9, so text starting BZh is not mistaken for bzip2
+			if len(head) < 4 || head[3] < '1' || head[3] > '9' {
+				continue
+			}
+			return io.NopCloser(bzip2.NewReader(br)), nil
+		}
+	}
+	return io.NopCloser(br), nil
+}
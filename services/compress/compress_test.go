@@ -0,0 +1,82 @@
+package compress_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/kream404/spoof/services/compress"
+	"github.com/stretchr/testify/assert"
+)
+
+const text = "id,code\n1,AB\n"
+
+// bzip2Text is text compressed with python's bz2 module; there is no bzip2 writer to make it.
+const bzip2Text = "\x42\x5a\x68\x39\x31\x41\x59\x26\x53\x59\x16\x39\x14\xc8\x00\x00\x05\xdd\x00\x00\x10\x00\x04\x20\x00\x30\x00\x0e\x20\xa0\x00\x22\x03\x26\x9a\x10\x03\x04\x42\x07\x6b\x99\x2f\xc5\xdc\x91\x4e\x14\x24\x05\x8e\x45\x32\x00"
+
+func read(t *testing.T, src []byte) string {
+	t.Helper()
+	r, err := compress.NewReader(bytes.NewReader(src))
+	assert.NoError(t, err)
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, codec := range []string{"gzip", "zstd", "none"} {
+		t.Run(codec, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := compress.NewWriter(codec, &buf)
+			assert.NoError(t, err)
+			_, err = io.WriteString(w, text)
+			assert.NoError(t, err)
+			assert.NoError(t, w.Close())
+
+			if codec != "none" {
+				assert.NotEqual(t, text, buf.String())
+			}
+			assert.Equal(t, text, read(t, buf.Bytes()))
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"bzip2", bzip2Text, text},
+		{"plain text starting BZh", "BZh,code\nBZhx\n", "BZh,code\nBZhx\n"},
+		{"short plain text", "BZ", "BZ"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, read(t, []byte(tt.src)))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]string{"": compress.None, "none": compress.None, "GZ": compress.Gzip, " zstd ": compress.Zstd, "zst": compress.Zstd} {
+		got, err := compress.Parse(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := compress.Parse("bz2")
+	assert.ErrorContains(t, err, "bzip2 can be read but not written")
+	_, err = compress.Parse("lz4")
+	assert.ErrorContains(t, err, `unknown compression "lz4"`)
+}
+
+func TestExt(t *testing.T) {
+	assert.Equal(t, "ledger.csv.gz", compress.WithExt("ledger.csv", compress.Gzip))
+	assert.Equal(t, "ledger.csv.GZ", compress.WithExt("ledger.csv.GZ", compress.Gzip))
+	assert.Equal(t, "ledger.csv", compress.WithExt("ledger.csv", compress.None))
+	assert.Equal(t, "ledger.csv", compress.TrimExt("ledger.csv.zst"))
+	assert.Equal(t, "data.bz2.csv", compress.TrimExt("data.bz2.csv"))
+}
@@ -0,0 +1,450 @@
+package csv
+
+import (
+	"bytes"
+	jsonstd "encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
+	"github.com/kream404/spoof/services/json"
+)
+
+const (
+	avroNamespace = "spoof"
+	// avroBlockSize is the number of rows per OCF block
+	avroBlockSize = 1000
+)
+
+var (
+	avroNameRe      = regexp.MustCompile(`[^A-Za-z0-9_]`)
+	avroPlaceholder = regexp.MustCompile(`^\$\{([a-zA-Z0-9_]+)(?::([a-zA-Z0-9_]+))?\}$`)
+)
+
+// avroType is a node of the generated schema; json fields become records and arrays.
+type avroType struct {
+	kind   string // string | long | double | boolean | null | record | array | column
+	name   string // record full name
+	fields []avroField
+	items  *avroType
+	column column // kind column: a top level field typed by columnFor
+}
+
+type avroField struct {
+	name string // avro name
+	key  string // json key
+	typ  *avroType
+}
+
+// avroRowWriter writes an Avro object container file with a schema derived from the fields.
+type avroRowWriter struct {
+	w       *goavro.OCFWriter
+	record  *avroType
+	schema  []byte
+	pending []any
+}
+
+func newAvroRowWriter(file models.Entity, w io.Writer) (*avroRowWriter, error) {
+	record, err := avroRecord(file)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := jsonstd.MarshalIndent(record.schema(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	codec := "null"
+	if a := file.Config.Avro; a != nil && a.Codec != "" {
+		codec = strings.ToLower(a.Codec)
+	}
+	switch codec {
+	case goavro.CompressionNullLabel, goavro.CompressionDeflateLabel, goavro.CompressionSnappyLabel:
+	default:
+		return nil, fmt.Errorf("unsupported avro codec %q (expected null, deflate or snappy)", codec)
+	}
+
+	ocf, err := goavro.NewOCFWriter(goavro.OCFConfig{W: w, Schema: string(schema), CompressionName: codec})
+	if err != nil {
+		return nil, fmt.Errorf("avro schema: %w", err)
+	}
+	return &avroRowWriter{w: ocf, record: record, schema: schema}, nil
+}
+
+func (a *avroRowWriter) WriteHeader([]string) error { return nil }
+
+func (a *avroRowWriter) WriteRow(_ []string, values []models.Value) error {
+	datum := make(map[string]any, len(values))
+	for i, f := range a.record.fields {
+		v, err := avroValue(f.typ.column, f.typ, values[i])
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.typ.column.Field.Name, err)
+		}
+		datum[f.name] = v
+	}
+	a.pending = append(a.pending, datum)
+	if len(a.pending) >= avroBlockSize {
+		return a.Flush()
+	}
+	return nil
+}
+
+func (a *avroRowWriter) Flush() error {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	err := a.w.Append(a.pending)
+	a.pending = a.pending[:0]
+	return err
+}
+
+// Schema returns the generated schema as JSON (.avsc).
+func (a *avroRowWriter) Schema() []byte { return a.schema }
+
+func exportAvroSchema(file models.Entity) bool {
+	return file.Config.Avro != nil && file.Config.Avro.ExportSchema
+}
+
+// avroSchemaPath is the .avsc written next to a data file.
+func avroSchemaPath(path string) string {
+	path = compress.TrimExt(path)
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".avsc"
+}
+
+func avroName(s string) string {
+	s = avroNameRe.ReplaceAllString(s, "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "_" + s
+	}
+	return s
+}
+
+func avroRecord(file models.Entity) (*avroType, error) {
+	namespace, name := avroNamespace, ""
+	if a := file.Config.Avro; a != nil {
+		if a.Namespace != "" {
+			namespace = a.Namespace
+		}
+		name = a.Record
+	}
+	if name == "" {
+		base := filepath.Base(file.Config.FileName)
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	record := &avroType{kind: "record", name: namespace + "." + avroName(name)}
+	columns, err := outputColumns(file.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]string{}
+	for _, c := range columns {
+		fname := avroName(c.Field.Name)
+		if prev, ok := seen[fname]; ok {
+			return nil, fmt.Errorf("fields %s and %s have the same avro name %s", prev, c.Field.Name, fname)
+		}
+		seen[fname] = c.Field.Name
+
+		typ := &avroType{kind: "column", column: c}
+		if c.Type == colJSON {
+			if typ.items, err = avroJSONType(c.Field, record.name+"_"+fname); err != nil {
+				return nil, err
+			}
+		}
+		record.fields = append(record.fields, avroField{name: fname, key: c.Field.Name, typ: typ})
+	}
+	return record, nil
+}
+
+// avroJSONType derives a record or array type from a json field's template.
+func avroJSONType(f models.Field, name string) (*avroType, error) {
+	cj, err := json.CompileJSONField(f, f.Template)
+	if err != nil {
+		return nil, err
+	}
+	dec := jsonstd.NewDecoder(strings.NewReader(cj.Raw))
+	dec.UseNumber()
+	node, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: invalid template: %w", f.Name, err)
+	}
+	if _, ok := node.(orderedObject); !ok {
+		if _, ok := node.([]any); !ok {
+			return nil, fmt.Errorf("field %s: template must be an object or array", f.Name)
+		}
+	}
+	return avroTemplateType(node, name), nil
+}
+
+func avroTemplateType(node any, name string) *avroType {
+	switch n := node.(type) {
+	case orderedObject:
+		t := &avroType{kind: "record", name: name}
+		for _, key := range n.keys {
+			fname := avroName(key)
+			t.fields = append(t.fields, avroField{name: fname, key: key, typ: avroTemplateType(n.values[key], name+"_"+fname)})
+		}
+		return t
+	case []any:
+		item := &avroType{kind: "string"}
+		if len(n) > 0 {
+			item = avroTemplateType(n[0], name+"_item")
+		}
+		return &avroType{kind: "array", items: item}
+	case string:
+		m := avroPlaceholder.FindStringSubmatch(n)
+		if m == nil {
+			return &avroType{kind: "string"}
+		}
+		switch strings.ToLower(m[2]) {
+		case "number":
+			return &avroType{kind: "double"}
+		case "bool", "boolean":
+			return &avroType{kind: "boolean"}
+		}
+		return &avroType{kind: "string"}
+	case jsonstd.Number:
+		if _, err := n.Int64(); err == nil {
+			return &avroType{kind: "long"}
+		}
+		return &avroType{kind: "double"}
+	case bool:
+		return &avroType{kind: "boolean"}
+	default:
+		return &avroType{kind: "null"}
+	}
+}
+
+// schema renders the type as an avro schema.
+func (t *avroType) schema() any {
+	switch t.kind {
+	case "record":
+		fields := make([]any, len(t.fields))
+		for i, f := range t.fields {
+			field := map[string]any{"name": f.name, "type": f.typ.schema()}
+			if f.typ.kind == "column" && f.typ.column.Nullable {
+				field["default"] = nil
+			}
+			fields[i] = field
+		}
+		ns, name := splitAvroName(t.name)
+		return map[string]any{"type": "record", "name": name, "namespace": ns, "fields": fields}
+	case "array":
+		return map[string]any{"type": "array", "items": t.items.schema()}
+	case "column":
+		var s any
+		switch t.column.Type {
+		case colLong:
+			s = "long"
+		case colDouble:
+			s = "double"
+		case colDecimal:
+			s = map[string]any{"type": "bytes", "logicalType": "decimal", "precision": decimalPrecision, "scale": t.column.Scale}
+		case colTimestamp:
+			s = map[string]any{"type": "long", "logicalType": "timestamp-micros"}
+		case colBool:
+			s = "boolean"
+		case colUUID:
+			s = map[string]any{"type": "string", "logicalType": "uuid"}
+		case colJSON:
+			s = t.items.schema()
+		default:
+			s = "string"
+		}
+		if t.column.Nullable {
+			return []any{"null", s}
+		}
+		return s
+	default:
+		return t.kind
+	}
+}
+
+func splitAvroName(full string) (namespace, name string) {
+	i := strings.LastIndex(full, ".")
+	return full[:i], full[i+1:]
+}
+
+// unionName is the branch name goavro uses for a nullable column's type.
+func (t *avroType) unionName() string {
+	switch t.column.Type {
+	case colLong:
+		return "long"
+	case colDouble:
+		return "double"
+	case colDecimal:
+		return "bytes.decimal"
+	case colTimestamp:
+		return "long.timestamp-micros"
+	case colBool:
+		return "boolean"
+	case colJSON:
+		if t.items.kind == "record" {
+			return t.items.name
+		}
+		return t.items.kind
+	default:
+		return "string"
+	}
+}
+
+// avroValue converts a top level value to its goavro native form.
+func avroValue(c column, t *avroType, v models.Value) (any, error) {
+	if v.IsNull() {
+		if !c.Nullable {
+			return nil, fmt.Errorf("null value in a non-nullable column (set null_rate to make it nullable)")
+		}
+		return nil, nil
+	}
+
+	var out any
+	var err error
+	switch c.Type {
+	case colLong:
+		out, err = longValue(v)
+	case colDouble:
+		out, err = doubleValue(v)
+	case colDecimal:
+		d, derr := scaledDecimal(v, c.Scale)
+		if derr != nil {
+			return nil, derr
+		}
+		out = d.Shift(-c.Scale).Rat()
+	case colTimestamp:
+		out, err = timestampValue(v, c.Field)
+	case colBool:
+		out, err = boolValue(v)
+	case colUUID:
+		u, uerr := uuidValue(v)
+		out, err = u.String(), uerr
+	case colJSON:
+		dec := jsonstd.NewDecoder(bytes.NewReader([]byte(v.Text)))
+		dec.UseNumber()
+		var node any
+		if err := dec.Decode(&node); err != nil {
+			return nil, fmt.Errorf("invalid json: %w", err)
+		}
+		out, err = avroJSONValue(t.items, node)
+	default:
+		out = v.Text
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Nullable {
+		return goavro.Union(t.unionName(), out), nil
+	}
+	return out, nil
+}
+
+// avroJSONValue converts a decoded json value to the template's type.
+func avroJSONValue(t *avroType, node any) (any, error) {
+	switch t.kind {
+	case "record":
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object, got %T", node)
+		}
+		out := make(map[string]any, len(t.fields))
+		for _, f := range t.fields {
+			v, err := avroJSONValue(f.typ, m[f.key])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.key, err)
+			}
+			out[f.name] = v
+		}
+		return out, nil
+	case "array":
+		items, ok := node.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an array, got %T", node)
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			v, err := avroJSONValue(t.items, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	case "long":
+		if n, ok := node.(jsonstd.Number); ok {
+			return n.Int64()
+		}
+	case "double":
+		if n, ok := node.(jsonstd.Number); ok {
+			return n.Float64()
+		}
+	case "boolean":
+		if b, ok := node.(bool); ok {
+			return b, nil
+		}
+	case "string":
+		switch n := node.(type) {
+		case string:
+			return n, nil
+		case jsonstd.Number:
+			return n.String(), nil
+		case bool:
+			return fmt.Sprint(n), nil
+		}
+	case "null":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("expected %s, got %v", t.kind, node)
+}
+
+// orderedObject is a json object that keeps its key order, so records match the template.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func decodeOrdered(dec *jsonstd.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case jsonstd.Delim('{'):
+		obj := orderedObject{values: map[string]any{}}
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := k.(string)
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = v
+		}
+		_, err = dec.Token()
+		return obj, err
+	case jsonstd.Delim('['):
+		var arr []any
+		for dec.More() {
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token()
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
@@ -0,0 +1,119 @@
+package csv
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/evaluator"
+)
+
+// controlToken matches ${...}, and $${...}, which is written as a literal ${...}.
+var controlToken = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// controlTotals is what ${...} tokens in a header or footer line render from.
+type controlTotals struct {
+	agg       *evaluator.Aggregate // every detail row in the file
+	fileIndex int                  // 1-based, out of fileCount
+	fileCount int
+	seed      string
+	now       time.Time
+}
+
+// renderControlLine replaces the tokens in a header or footer line:
+//
+//	${row_count}                 rows written
+//	${sum:col} ${min:col} ${max:col} ${avg:col}
+//	                             totals of a column, with an optional :places suffix
+//	${file_index} ${file_count}  this file's number out of file_count
+//	${seed}                      the seed the file was generated with
+//	${date} ${date:layout}       the current date, by default 2006-01-02
+//
+// $${ is written as a literal ${.
+func renderControlLine(line string, t controlTotals) (string, error) {
+	var firstErr error
+	out := controlToken.ReplaceAllStringFunc(line, func(token string) string {
+		if strings.HasPrefix(token, "$$") {
+			return token[1:]
+		}
+		v, err := controlValue(token[2:len(token)-1], t)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("token %s: %w", token, err)
+		}
+		return v
+	})
+	return out, firstErr
+}
+
+func controlValue(token string, t controlTotals) (string, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(token), ":")
+	switch name {
+	case "row_count":
+		return strconv.Itoa(t.agg.Count), nil
+	case "file_index":
+		return strconv.Itoa(t.fileIndex), nil
+	case "file_count":
+		return strconv.Itoa(t.fileCount), nil
+	case "seed":
+		return t.seed, nil
+	case "date":
+		if arg == "" {
+			arg = "2006-01-02"
+		}
+		return t.now.Format(arg), nil
+	case "sum", "min", "max", "avg":
+		column, places, _ := strings.Cut(arg, ":")
+		if column == "" {
+			return "", fmt.Errorf("%s requires a column, e.g. ${%s:amount}", name, name)
+		}
+		v, err := t.agg.Value(models.Field{Function: name, Target: column, Format: places})
+		if err != nil {
+			return "", err
+		}
+		return v.Text, nil
+	default:
+		return "", fmt.Errorf("unknown token (expected row_count, sum, min, max, avg, file_index, file_count, seed or date)")
+	}
+}
+
+// validateControlLine checks a header or footer line's tokens, and that column
+// totals name one of the fields.
+func validateControlLine(line string, fields []models.Field) error {
+	for _, m := range controlToken.FindAllStringSubmatch(line, -1) {
+		if strings.HasPrefix(m[0], "$$") {
+			continue
+		}
+		name, arg, _ := strings.Cut(strings.TrimSpace(m[1]), ":")
+		switch name {
+		case "sum", "min", "max", "avg":
+			column, _, _ := strings.Cut(arg, ":")
+			if !hasField(fields, column) {
+				return fmt.Errorf("token %s: no field named %q", m[0], column)
+			}
+		}
+	}
+	_, err := renderControlLine(line, controlTotals{agg: evaluator.NewAggregate()})
+	return err
+}
+
+// hasControlTokens reports whether line has tokens to render, other than escaped ones.
+func hasControlTokens(line string) bool {
+	for _, token := range controlToken.FindAllString(line, -1) {
+		if !strings.HasPrefix(token, "$$") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasField(fields []models.Field, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -2,25 +2,29 @@ package csv
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 	"github.com/kream404/spoof/services/detector"
 	log "github.com/kream404/spoof/services/logger"
 )
 
 // returns records from csv, file, delim, easier to do all this on read
 func ReadCSV(path string) ([][]string, string, rune, string, string, error) {
-	file, err := os.Open(path)
+	data, err := readSource(path)
 	if err != nil {
 		return nil, "", 0, "", "", err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	var lines []string
 
 	for scanner.Scan() {
@@ -61,17 +65,36 @@ func ReadCSV(path string) ([][]string, string, rune, string, string, error) {
 		return nil, "", 0, "", "", err
 	}
 
-	return records, file.Name(), delimiter, header, footer, nil
+	return records, path, delimiter, header, footer, nil
+}
+
+// readSource reads a source file, decompressing gzip, zstd and bzip2.
+func readSource(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dec, err := compress.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("decompress %s: %w", path, err)
+	}
+	defer dec.Close()
+	data, err := io.ReadAll(dec)
+	if err != nil {
+		return nil, fmt.Errorf("decompress %s: %w", path, err)
+	}
+	return data, nil
 }
 
 func ReadCSVAsMap(filepath string) ([]map[string]any, []string, rune, error) {
-	file, err := os.Open(filepath)
+	data, err := readSource(filepath)
 	if err != nil {
 		return nil, nil, 0, err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	var firstLine string
 	if scanner.Scan() {
 		firstLine = scanner.Text()
@@ -81,11 +104,7 @@ func ReadCSVAsMap(filepath string) ([]map[string]any, []string, rune, error) {
 
 	delimiter := DetectDelimiter(firstLine)
 
-	// rewind and read all with encoding/csv
-	if _, err := file.Seek(0, 0); err != nil {
-		return nil, nil, 0, err
-	}
-	r := csv.NewReader(file)
+	r := csv.NewReader(bytes.NewReader(data))
 	r.Comma = delimiter
 
 	records, err := r.ReadAll()
@@ -173,3 +192,109 @@ func DetectDelimiter(line string) rune {
 	}
 	return ','
 }
+
+// ReadFixedWidth returns the data lines of a fixed-width file, with the header
+// and footer records split off. A first or last line is a header or footer when
+// its length differs from the data records, or it has a character at some
+// position that no data record has there: text where they are blank, a letter
+// where they all have digits, and so on.
+func ReadFixedWidth(path string) ([]string, string, string, string, error) {
+	raw, err := readSource(path)
+	if err != nil {
+		return nil, "", "", "", err
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(raw), "\r\n", "\n"), "\n"), "\n")
+	if len(lines) < 3 {
+		return nil, "", "", "", fmt.Errorf("file does not contain enough lines to have header/data/footer")
+	}
+
+	var header, footer string
+	body := lines[1 : len(lines)-1]
+	if outlierRecord(lines[0], body) {
+		header, lines = strings.TrimRight(lines[0], " "), lines[1:]
+	}
+	if outlierRecord(lines[len(lines)-1], body) {
+		footer, lines = strings.TrimRight(lines[len(lines)-1], " "), lines[:len(lines)-1]
+	}
+	return lines, path, header, footer, nil
+}
+
+func outlierRecord(line string, body []string) bool {
+	counts := make(map[int]int)
+	modal := 0
+	for _, b := range body {
+		n := utf8.RuneCountInString(b)
+		counts[n]++
+		if counts[n] > counts[modal] {
+			modal = n
+		}
+	}
+	r := []rune(line)
+	if len(r) != modal {
+		return true
+	}
+
+	for i, c := range r {
+		if c == ' ' {
+			continue
+		}
+		seen := false
+		for _, b := range body {
+			if br := []rune(b); i < len(br) && charClass(br[i]) == charClass(c) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			return true
+		}
+	}
+	return false
+}
+
+func charClass(r rune) int {
+	switch {
+	case r == ' ':
+		return 0
+	case unicode.IsDigit(r):
+		return 1
+	case unicode.IsLetter(r):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// MapFixedFields infers columns from fixed-width lines and maps each to a field
+// with its width, align and pad_char. Columns are named col_1, col_2, ...
+func MapFixedFields(lines []string) ([]models.Field, []string, error) {
+	spans := detector.InferFixedWidth(lines)
+	if len(spans) == 0 {
+		return nil, nil, fmt.Errorf("no columns found in fixed-width data")
+	}
+
+	names := make([]string, len(spans))
+	for i := range spans {
+		names[i] = fmt.Sprintf("col_%d", i+1)
+	}
+	records := [][]string{names}
+	for _, line := range lines {
+		records = append(records, detector.SplitFixed(line, spans))
+	}
+
+	fields, types, err := MapFields(records)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, span := range spans {
+		raw := make([]string, 0, len(lines))
+		for _, line := range lines {
+			r := []rune(line)
+			start, end := min(span.Start, len(r)), min(span.Start+span.Width, len(r))
+			raw = append(raw, string(r[start:end])+strings.Repeat(" ", span.Width-(end-start)))
+		}
+		fields[i].Width = span.Width
+		fields[i].Align, fields[i].PadChar = detector.InferFixedLayout(raw)
+	}
+	return fields, types, nil
+}
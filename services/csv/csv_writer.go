@@ -3,10 +3,10 @@ package csv
 import (
 	"bufio"
 	"context"
-	"encoding/csv"
 	jsonstd "encoding/json"
 	"fmt"
 	"hash/fnv"
+	"io"
 	"math/rand"
 	"net/url"
 	"os"
@@ -19,16 +19,39 @@ import (
 	"github.com/briandowns/spinner"
 	"github.com/google/uuid"
 
+	"github.com/kream404/spoof/fakers"
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 	"github.com/kream404/spoof/services/database"
 	"github.com/kream404/spoof/services/evaluator" // ✅ new
 	log "github.com/kream404/spoof/services/logger"
+	"github.com/kream404/spoof/services/pgp"
 	s3c "github.com/kream404/spoof/services/s3"
 )
 
+// StdoutPath is the output directory or file_name that writes to stdout.
+const StdoutPath = "-"
+
 func ProcessFiles(config models.FileConfig, force bool, dryRun bool) error {
+	return ProcessFilesTo(config, "output", force, dryRun)
+}
+
+// ProcessFilesTo writes the files into outDir, or to stdout when outDir or a
+// file's file_name is StdoutPath.
+func ProcessFilesTo(config models.FileConfig, outDir string, force bool, dryRun bool) error {
 	ctx := context.Background()
 	acc := &OutputAccumulator{}
+	books := make(workbooks)
+
+	if err := checkStdout(config, outDir, dryRun); err != nil {
+		log.Error("cannot write to stdout", "err", err)
+		return err
+	}
+	// outputs go to stderr when stdout carries the data
+	accOut := io.Writer(os.Stdout)
+	if writesToStdout(config, outDir) {
+		accOut = os.Stderr
+	}
 
 	for _, file := range config.Files {
 		if file.Config.FileCount <= 0 {
@@ -39,13 +62,13 @@ func ProcessFiles(config models.FileConfig, force bool, dryRun bool) error {
 			iterFile := file
 			iterFile.Config.FileName = withIndexSuffix(file.Config.FileName, i, file.Config.FileCount)
 
-			if err := processOneFile(ctx, iterFile, "output", force, dryRun, acc); err != nil {
+			if err := processOneFile(ctx, iterFile, i+1, outDir, force, dryRun, acc, books); err != nil {
 				log.Error("file processing failed", "file", iterFile.Config.FileName, "err", err)
 				return err
 			}
 		}
 
-		if err := acc.FlushToStdout(file.Config.FileName); err != nil {
+		if err := acc.Flush(accOut, file.Config.FileName); err != nil {
 			return err
 		}
 	}
@@ -53,7 +76,46 @@ func ProcessFiles(config models.FileConfig, force bool, dryRun bool) error {
 	return nil
 }
 
-func processOneFile(ctx context.Context, file models.Entity, outDir string, force bool, dryRun bool, acc *OutputAccumulator) error {
+func toStdout(outDir string, file models.Entity) bool {
+	return outDir == StdoutPath || file.Config.FileName == StdoutPath
+}
+
+func writesToStdout(config models.FileConfig, outDir string) bool {
+	for _, file := range config.Files {
+		if toStdout(outDir, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStdout rejects what can't be written to stdout: post-processing, which
+// reads the local file, and a binary file followed or preceded by other files.
+func checkStdout(config models.FileConfig, outDir string, dryRun bool) error {
+	count, binary := 0, ""
+	for _, file := range config.Files {
+		if !toStdout(outDir, file) {
+			continue
+		}
+		// encryption also runs on dry runs, and stdout would carry the plaintext
+		if file.Postprocess.Enabled && file.Postprocess.Encrypt != nil {
+			return fmt.Errorf("%s is written to stdout, so it can't be encrypted; write it to a file", file.Config.FileName)
+		}
+		if file.Postprocess.Enabled && !dryRun {
+			return fmt.Errorf("%s is written to stdout, so it can't be post-processed; write it to a file or pass --dry run", file.Config.FileName)
+		}
+		count += max(file.Config.FileCount, 1)
+		if !textFormat(outputFormat(file.Config)) && outputFormat(file.Config) != FormatXML {
+			binary = outputFormat(file.Config)
+		}
+	}
+	if count > 1 && binary != "" {
+		return fmt.Errorf("only one file can be written to stdout when it is %s", binary)
+	}
+	return nil
+}
+
+func processOneFile(ctx context.Context, file models.Entity, fileIndex int, outDir string, force bool, dryRun bool, acc *OutputAccumulator, books workbooks) error {
 	if err := validateEntityConfig(file); err != nil {
 		return fmt.Errorf("%w", err)
 	}
@@ -81,11 +143,19 @@ func processOneFile(ctx context.Context, file models.Entity, outDir string, forc
 	)
 
 	if file.Fields != nil {
-		localPath, err = generateCSV(file, outDir, acc)
+		localPath, err = generateFile(file, fileIndex, outDir, acc, books)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to generate CSV for %q: %w", file.Config.FileName, err)
+		return fmt.Errorf("failed to generate %s for %q: %w", outputFormat(file.Config), file.Config.FileName, err)
+	}
+
+	// encryption only writes locally, so it runs on dry runs too
+	uploadPath := localPath
+	if encPath, err := Encrypt(file, localPath); err != nil {
+		return fmt.Errorf("encryption failed for %q: %w", file.Config.FileName, err)
+	} else if encPath != "" {
+		uploadPath = encPath
 	}
 
 	if dryRun {
@@ -101,7 +171,7 @@ func processOneFile(ctx context.Context, file models.Entity, outDir string, forc
 		return fmt.Errorf("database insert failed for %q: %w", file.Config.FileName, err)
 	}
 
-	if err := UploadToS3(ctx, file, localPath); err != nil {
+	if err := UploadToS3(ctx, file, uploadPath); err != nil {
 		return fmt.Errorf("S3 upload failed for %q: %w", file.Config.FileName, err)
 	}
 
@@ -131,7 +201,9 @@ func Delete(ctx context.Context, file models.Entity) error {
 	return nil
 }
 
-func generateCSV(file models.Entity, outDir string, acc *OutputAccumulator) (string, error) {
+// generateFile writes the entity's rows in its config.format. fileIndex is the
+// file's 1-based number out of file_count; books are the xlsx workbooks written so far.
+func generateFile(file models.Entity, fileIndex int, outDir string, acc *OutputAccumulator, books workbooks) (string, error) {
 	var cacheIndex, rowIndex = 0, 1
 
 	log.Info("Generating file", "file", file.Config.FileName)
@@ -141,36 +213,79 @@ func generateCSV(file models.Entity, outDir string, acc *OutputAccumulator) (str
 		return "", fmt.Errorf("could not load cache: %w", err)
 	}
 
-	outFile, localPath, err := makeOutputFile(outDir, file.Config.FileName)
+	outFile, localPath, err := makeOutputFile(outDir, outputName(file.Config))
 	if err != nil {
 		return "", fmt.Errorf("create output file: %w", err)
 	}
 	defer outFile.Close()
 
-	tempWriter := &strings.Builder{}
-	writer := csv.NewWriter(tempWriter)
-	if d := file.Config.Delimiter; len(d) > 0 && d[0] != 0 {
-		writer.Comma = rune(d[0])
+	rng, seed := CreateRNGSeed(file.Config.Seed)
+
+	compressed, err := compress.NewWriter(file.Config.Compression, outFile)
+	if err != nil {
+		return "", err
+	}
+	defer compressed.Close() // on failure; otherwise it is closed below, where its error is checked
+	out := bufio.NewWriter(compressed)
+
+	// rows stream to out, unless something written before them needs their
+	// totals: then they are spooled to a temp file until it has been rendered
+	var rows *spool
+	var layout *recordLayout
+	var detailOut io.Writer = out
+	switch {
+	case len(file.Records) > 0:
+		if layout, err = newRecordLayout(file, out, rng); err != nil {
+			return "", err
+		}
+		if err := layout.open(); err != nil {
+			return "", err
+		}
+		defer layout.release()
+		detailOut = layout.batch
+	case hasControlTokens(file.Config.Header) || outputFormat(file.Config) == FormatXML:
+		if rows, err = newSpool(); err != nil {
+			return "", err
+		}
+		defer rows.Close()
+		detailOut = rows
+	}
+
+	writer, err := newRowWriter(file, detailOut)
+	if err != nil {
+		return "", err
+	}
+	if layout != nil {
+		layout.detail = writer
+	}
+	if xw, ok := writer.(*xlsxRowWriter); ok {
+		if err := xw.join(books, localPath); err != nil {
+			return "", err
+		}
 	}
 
-	if file.Config.IncludeHeaders {
-		headers := make([]string, 0, len(file.Fields))
-		for _, field := range file.Fields {
-			if field.Skip {
-				continue
-			}
-			headers = append(headers, field.Name)
+	if file.Config.Header != "" && !hasControlTokens(file.Config.Header) {
+		if err := writeControlLine(out, writer, file.Config.Header, controlTotals{}); err != nil {
+			return "", fmt.Errorf("write header: %w", err)
 		}
-		if err := writer.Write(headers); err != nil {
-			return "", fmt.Errorf("CSV Write Error (headers): %v", err)
+	}
+
+	headers := make([]string, 0, len(file.Fields))
+	for _, field := range file.Fields {
+		if field.Skip {
+			continue
 		}
+		headers = append(headers, field.Name)
+	}
+	if err := writer.WriteHeader(headers); err != nil {
+		return "", fmt.Errorf("write headers: %v", err)
 	}
 
 	fieldCaches := preloadFieldSources(file.Fields)
+	totals := evaluator.NewAggregate()
+	reused := evaluator.NewFakers(file.Config.RowCount)
 
-	rng, seed := CreateRNGSeed(file.Config.Seed)
-
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond, spinner.WithWriterFile(os.Stderr))
 	s.Suffix = fmt.Sprintf(" Generating %s (%d rows)...", file.Config.FileName, file.Config.RowCount)
 	s.Start()
 
@@ -182,6 +297,7 @@ func generateCSV(file models.Entity, outDir string, acc *OutputAccumulator) (str
 			rowIndex,
 			cacheIndex,
 			rng,
+			reused,
 		)
 		if err != nil {
 			s.Stop()
@@ -193,9 +309,16 @@ func generateCSV(file models.Entity, outDir string, acc *OutputAccumulator) (str
 			return "", fmt.Errorf("output hook: %w", err)
 		}
 
-		if err := writer.Write(row); err != nil {
+		if err := writer.WriteRow(headers, row); err != nil {
 			s.Stop()
-			return "", fmt.Errorf("CSV write row: %w", err)
+			return "", fmt.Errorf("write row: %w", err)
+		}
+		totals.Add(generated)
+		if layout != nil {
+			if err := layout.add(generated); err != nil {
+				s.Stop()
+				return "", fmt.Errorf("write batch: %w", err)
+			}
 		}
 
 		cacheIndex++
@@ -210,31 +333,79 @@ func generateCSV(file models.Entity, outDir string, acc *OutputAccumulator) (str
 		}
 	}
 
-	writer.Flush()
-	if err := writer.Error(); err != nil {
+	if err := writer.Flush(); err != nil {
 		s.Stop()
-		return "", fmt.Errorf("CSV flush: %w", err)
+		return "", fmt.Errorf("flush: %w", err)
 	}
 
-	finalWriter := bufio.NewWriter(outFile)
-	if file.Config.Header != "" {
-		_, _ = finalWriter.WriteString(file.Config.Header + "\n")
+	control := controlTotals{agg: totals, fileIndex: fileIndex, fileCount: max(file.Config.FileCount, 1), seed: seed, now: time.Now()}
+	if hasControlTokens(file.Config.Header) {
+		if err := writeControlLine(out, writer, file.Config.Header, control); err != nil {
+			s.Stop()
+			return "", fmt.Errorf("write header: %w", err)
+		}
+	}
+	if layout != nil {
+		if err := layout.close(); err != nil {
+			s.Stop()
+			return "", fmt.Errorf("write records: %w", err)
+		}
+	}
+	if rows != nil {
+		if xw, ok := writer.(*xmlRowWriter); ok {
+			err = xw.Document(out, rows, control)
+		} else {
+			_, err = rows.WriteTo(out)
+		}
+		if err != nil {
+			s.Stop()
+			return "", err
+		}
 	}
-	_, _ = finalWriter.WriteString(tempWriter.String())
 	if file.Config.Footer != "" {
-		_, _ = finalWriter.WriteString(file.Config.Footer + "\n")
+		if err := writeControlLine(out, writer, file.Config.Footer, control); err != nil {
+			s.Stop()
+			return "", fmt.Errorf("write footer: %w", err)
+		}
 	}
 
-	if err := finalWriter.Flush(); err != nil {
+	if err := out.Flush(); err != nil {
 		s.Stop()
 		return "", fmt.Errorf("flush final writer: %w", err)
 	}
+	if err := compressed.Close(); err != nil {
+		s.Stop()
+		return "", fmt.Errorf("close %s stream: %w", file.Config.Compression, err)
+	}
+
+	if aw, ok := writer.(*avroRowWriter); ok && exportAvroSchema(file) && localPath != StdoutPath {
+		if err := os.WriteFile(avroSchemaPath(localPath), aw.Schema(), 0o644); err != nil {
+			s.Stop()
+			return "", fmt.Errorf("write avro schema: %w", err)
+		}
+	}
 
 	s.Stop()
-	log.Info("CSV generated", "path", localPath, "seed", seed)
+	log.Info("File generated", "path", localPath, "format", outputFormat(file.Config), "seed", seed)
 	return localPath, nil
 }
 
+// writeControlLine renders a header or footer line and writes it to w, padded
+// to the record width in fixed-width files.
+func writeControlLine(w io.Writer, writer RowWriter, line string, control controlTotals) error {
+	rendered, err := renderControlLine(line, control)
+	if err != nil {
+		return err
+	}
+	if fw, ok := writer.(*fixedRowWriter); ok {
+		if rendered, err = fw.Record(rendered); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, rendered+"\n")
+	return err
+}
+
 func Insert(ctx context.Context, file models.Entity, localPath string) error {
 	pp := file.Postprocess
 	if !pp.Enabled {
@@ -297,6 +468,69 @@ func LoadCache(config *models.CacheConfig) ([]map[string]any, error) {
 	return cache, nil
 }
 
+// Encrypt writes the file PGP-encrypted for postprocess.encrypt's recipients
+// next to localPath, and returns its path, or "" when encryption isn't set.
+func Encrypt(file models.Entity, localPath string) (string, error) {
+	opts := file.Postprocess.Encrypt
+	if !file.Postprocess.Enabled || opts == nil || localPath == "" {
+		return "", nil
+	}
+	if localPath == StdoutPath {
+		return "", fmt.Errorf("output written to stdout can't be encrypted")
+	}
+
+	var recipients pgp.KeyRing
+	for _, path := range opts.Recipients {
+		keys, err := pgp.ReadKeys(path)
+		if err != nil {
+			return "", err
+		}
+		recipients = append(recipients, keys...)
+	}
+	var signer *pgp.Key
+	if opts.SignKey != "" {
+		var err error
+		if signer, err = pgp.ReadPrivateKey(opts.SignKey, opts.Passphrase); err != nil {
+			return "", err
+		}
+	}
+
+	src, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	encPath := localPath + encryptedExt(opts)
+	dst, err := os.Create(encPath)
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+
+	if err := pgp.Encrypt(dst, src, localPath, recipients, signer, opts.Armor); err != nil {
+		return "", err
+	}
+	if err := dst.Close(); err != nil {
+		return "", err
+	}
+
+	log.Info("Encrypted file", "path", encPath, "recipients", len(recipients), "signed", signer != nil)
+	return encPath, nil
+}
+
+// encryptedExt is .asc for armored and .gpg for binary PGP files, or "" without encryption.
+func encryptedExt(opts *models.EncryptOptions) string {
+	switch {
+	case opts == nil:
+		return ""
+	case opts.Armor:
+		return ".asc"
+	default:
+		return ".gpg"
+	}
+}
+
 func UploadToS3(ctx context.Context, file models.Entity, localPath string) error {
 	pp := file.Postprocess
 	if !pp.Enabled {
@@ -311,7 +545,7 @@ func UploadToS3(ctx context.Context, file models.Entity, localPath string) error
 		return fmt.Errorf("init S3 connector: %w", err)
 	}
 
-	dest, err := joinS3URI(pp.Location, file.Config.FileName)
+	dest, err := joinS3URI(pp.Location, outputName(file.Config)+encryptedExt(pp.Encrypt))
 	if err != nil {
 		return err
 	}
@@ -320,11 +554,33 @@ func UploadToS3(ctx context.Context, file models.Entity, localPath string) error
 		return fmt.Errorf("upload to S3: %w", err)
 	}
 
-	log.Info("Uploaded CSV to S3", "uri", dest)
+	log.Info("Uploaded file to S3", "uri", dest)
+
+	if outputFormat(file.Config) == FormatAvro && exportAvroSchema(file) {
+		schemaDest := avroSchemaPath(dest)
+		if _, err := s3.UploadFile(ctx, schemaDest, avroSchemaPath(localPath)); err != nil {
+			return fmt.Errorf("upload avro schema to S3: %w", err)
+		}
+		log.Info("Uploaded avro schema to S3", "uri", schemaDest)
+	}
 	return nil
 }
 
-func makeOutputFile(baseDir, fileName string) (*os.File, string, error) {
+// outputName is the file name with the extension of config.compression, e.g. payments.csv.gz.
+func outputName(config models.Config) string {
+	if config.FileName == StdoutPath {
+		return StdoutPath
+	}
+	codec, _ := compress.Parse(config.Compression)
+	return compress.WithExt(config.FileName, codec)
+}
+
+// makeOutputFile creates the file under baseDir, or returns stdout, which
+// isn't closed, when either is StdoutPath.
+func makeOutputFile(baseDir, fileName string) (io.WriteCloser, string, error) {
+	if baseDir == StdoutPath || fileName == StdoutPath {
+		return nopWriteCloser{os.Stdout}, StdoutPath, nil
+	}
 	if strings.TrimSpace(baseDir) == "" {
 		baseDir = "output"
 	}
@@ -352,6 +608,10 @@ func makeOutputFile(baseDir, fileName string) (*os.File, string, error) {
 	return f, outputFile, nil
 }
 
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
 type fieldCache map[string][]map[string]any
 
 func preloadFieldSources(fields []models.Field) fieldCache {
@@ -412,7 +672,7 @@ func joinS3URI(base, key string) (string, error) {
 }
 
 func withIndexSuffix(name string, i, count int) string {
-	if count <= 1 {
+	if count <= 1 || name == StdoutPath {
 		return name
 	}
 
@@ -433,12 +693,20 @@ func validateEntityConfig(file models.Entity) error {
 		missing = append(missing, "config.fileName")
 	}
 
-	if file.Config.Delimiter == "" {
+	format := outputFormat(file.Config)
+	if _, err := compress.Parse(file.Config.Compression); err != nil {
+		return fmt.Errorf("invalid config for %s: %w", file.Config.FileName, err)
+	}
+	if format == FormatCSV && file.Config.Delimiter == "" {
 		missing = append(missing, "config.delimiter")
 	}
 
 	// Database checks for insert/delete
 	if file.Postprocess.Enabled && strings.EqualFold(file.Postprocess.Location, "database") {
+		if format != FormatCSV {
+			return fmt.Errorf("database postprocessing for %s requires csv format (got %s)", file.Config.FileName, format)
+		}
+
 		if file.CacheConfig == nil {
 			return fmt.Errorf(
 				"missing database config for %s: you must pass a `profile` or inline `cache` configuration",
@@ -483,13 +751,54 @@ func validateEntityConfig(file models.Entity) error {
 		}
 	}
 
+	if file.Postprocess.Enabled && file.Postprocess.Encrypt != nil && len(file.Postprocess.Encrypt.Recipients) == 0 {
+		missing = append(missing, "postprocess.encrypt.recipients")
+	}
+
 	if len(missing) > 0 {
 		return fmt.Errorf("missing config for %s: %s", file.Config.FileName, strings.Join(missing, ", "))
 	}
 
+	if _, err := newRowWriter(file, io.Discard); err != nil {
+		return fmt.Errorf("invalid config for %s: %w", file.Config.FileName, err)
+	}
+	for _, line := range []string{file.Config.Header, file.Config.Footer} {
+		if err := validateControlLine(line, file.Fields); err != nil {
+			return fmt.Errorf("invalid config for %s: %w", file.Config.FileName, err)
+		}
+	}
+	if len(file.Records) > 0 {
+		if _, err := newRecordLayout(file, io.Discard, nil); err != nil {
+			return fmt.Errorf("invalid config for %s: %w", file.Config.FileName, err)
+		}
+	}
+	if !textFormat(format) && (file.Config.Header != "" || file.Config.Footer != "") {
+		return fmt.Errorf("invalid config for %s: header and footer are not supported by %s", file.Config.FileName, format)
+	}
+
+	problems := validateFields(file.Fields)
+	for _, r := range file.Records {
+		problems = append(problems, validateFields(r.Fields)...)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid fields in %s: %s", file.Config.FileName, strings.Join(problems, "; "))
+	}
+
 	return nil
 }
 
+// validateFields checks each field's attributes against its faker's metadata, including nested json fields.
+func validateFields(fields []models.Field) []string {
+	var problems []string
+	for _, f := range fields {
+		if err := fakers.ValidateField(f); err != nil {
+			problems = append(problems, err.Error())
+		}
+		problems = append(problems, validateFields(f.Fields)...)
+	}
+	return problems
+}
+
 func emitOutputHooks(file models.Entity, generated map[string]string, acc *OutputAccumulator) error {
 	if acc == nil || len(file.Output) == 0 {
 		return nil
@@ -576,6 +885,11 @@ func (a *OutputAccumulator) Add(item map[string]any) {
 }
 
 func (a *OutputAccumulator) FlushToStdout(fileName string) error {
+	return a.Flush(os.Stdout, fileName)
+}
+
+// Flush writes the accumulated outputs to w as JSON keyed by fileName.
+func (a *OutputAccumulator) Flush(w io.Writer, fileName string) error {
 	if len(a.items) == 0 {
 		return nil
 	}
@@ -589,7 +903,7 @@ func (a *OutputAccumulator) FlushToStdout(fileName string) error {
 		return fmt.Errorf("marshal output: %w", err)
 	}
 
-	fmt.Fprintln(os.Stdout, string(b))
+	fmt.Fprintln(w, string(b))
 	a.items = nil
 	return nil
 }
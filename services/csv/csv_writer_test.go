@@ -1,10 +1,25 @@
 package csv_test
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/linkedin/goavro/v2"
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/kream404/spoof/fakers"
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 	csvgen "github.com/kream404/spoof/services/csv"
 
 	// "github.com/kream404/spoof/services/json"
@@ -41,3 +56,703 @@ func TestMakeOutputDir(t *testing.T) {
 	err = os.RemoveAll("output")
 	assert.NoError(t, err)
 }
+
+func TestProcessFiles_JSONL(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "payload.json")
+	assert.NoError(t, os.WriteFile(template, []byte(`{"ref": "${ref}"}`), 0o644))
+
+	probability := 1.0
+	entity := models.Entity{
+		Config: models.Config{FileName: "events.jsonl", Format: "jsonl", RowCount: 2, FileCount: 2, Seed: "jsonl"},
+		Fields: []models.Field{
+			{Name: "id", Type: "iterator"},
+			{Name: "amount", Type: "number", Min: "1", Max: "10", Format: "2"},
+			{Name: "active", Type: "boolean", Format: "Y/N", Probability: &probability},
+			{Name: "note", Type: "", Value: "a \"quoted\" note"},
+			{Name: "payload", Type: "json", Template: template, Fields: []models.Field{
+				{Name: "ref", Type: "", Value: "abc"},
+			}},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	for _, name := range []string{"output/events_1.jsonl", "output/events_2.jsonl"} {
+		raw, err := os.ReadFile(name)
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+		assert.Len(t, lines, 2)
+		for _, line := range lines {
+			var row map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &row))
+			assert.IsType(t, float64(0), row["id"])
+			assert.IsType(t, float64(0), row["amount"])
+			assert.Equal(t, true, row["active"])
+			assert.Equal(t, `a "quoted" note`, row["note"])
+			assert.Equal(t, map[string]any{"ref": "abc"}, row["payload"])
+		}
+		assert.True(t, strings.HasPrefix(lines[0], `{"id":`), "keys should keep field order")
+	}
+}
+
+func TestProcessFiles_Parquet(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "ledger.parquet", Format: "parquet", RowCount: 5, Seed: "parquet",
+			Parquet: &models.ParquetOptions{RowGroupSize: 2, Compression: "zstd"},
+		},
+		Fields: []models.Field{
+			{Name: "id", Type: "uuid"},
+			{Name: "seq", Type: "iterator"},
+			{Name: "amount", Type: "number", Min: "1", Max: "10", Format: "2"},
+			{Name: "posted_at", Type: "timestamp", Format: "2006-01-02"},
+			{Name: "active", Type: "boolean"},
+			{Name: "copy", Type: "reflection", Target: "amount"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/ledger.parquet")
+	assert.NoError(t, err)
+	assert.Equal(t, "PAR1", string(raw[:4]))
+	assert.Equal(t, "PAR1", string(raw[len(raw)-4:]))
+
+	entity.Config.Header = "H"
+	err = csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.ErrorContains(t, err, "header and footer are not supported by parquet")
+
+	// decimal(18,2) holds at most 16 integer digits
+	entity.Config.Header = ""
+	entity.Fields = []models.Field{{Name: "amount", Type: "number", Min: "100000000000000000", Max: "900000000000000000", Format: "2"}}
+	for _, format := range []string{"parquet", "avro"} {
+		entity.Config.Format = format
+		err = csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+		assert.ErrorContains(t, err, "does not fit decimal(18,2)", format)
+	}
+}
+
+func TestProcessFiles_Avro(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "payload.json")
+	assert.NoError(t, os.WriteFile(template, []byte(`{"ref": "${ref}", "lines": [{"qty": "${qty:number}"}]}`), 0o644))
+
+	always := 100
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "ledger.avro", Format: "avro", RowCount: 3, Seed: "avro",
+			Avro: &models.AvroOptions{Codec: "deflate", ExportSchema: true},
+		},
+		Fields: []models.Field{
+			{Name: "id", Type: "uuid"},
+			{Name: "seq", Type: "iterator"},
+			{Name: "amount", Type: "number", Min: "1", Max: "10", Format: "2"},
+			{Name: "posted_at", Type: "timestamp", Format: "2006-01-02"},
+			{Name: "note", Type: "", Value: "x", NullRate: &always},
+			{Name: "payload", Type: "json", Template: template, Fields: []models.Field{
+				{Name: "ref", Type: "", Value: "abc"},
+				{Name: "qty", Type: "number", Min: "1", Max: "5", Format: "0"},
+			}},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	schema, err := os.ReadFile("output/ledger.avsc")
+	assert.NoError(t, err)
+	assert.Contains(t, string(schema), `"logicalType": "decimal"`)
+	assert.Contains(t, string(schema), `"logicalType": "timestamp-micros"`)
+	assert.Contains(t, string(schema), `"logicalType": "uuid"`)
+
+	f, err := os.Open("output/ledger.avro")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	r, err := goavro.NewOCFReader(f)
+	assert.NoError(t, err)
+
+	rows := 0
+	for r.Scan() {
+		datum, err := r.Read()
+		assert.NoError(t, err)
+		row := datum.(map[string]any)
+
+		assert.Equal(t, int64(rows+2), row["seq"])
+		assert.IsType(t, &big.Rat{}, row["amount"])
+		assert.IsType(t, time.Time{}, row["posted_at"])
+		assert.Nil(t, row["note"])
+
+		payload := row["payload"].(map[string]any)
+		assert.Equal(t, "abc", payload["ref"])
+		lines := payload["lines"].([]any)
+		assert.Len(t, lines, 1)
+		assert.IsType(t, float64(0), lines[0].(map[string]any)["qty"])
+		rows++
+	}
+	assert.Equal(t, 3, rows)
+}
+
+func TestProcessFiles_SQL(t *testing.T) {
+	fields := []models.Field{
+		{Name: "id", Type: "iterator"},
+		{Name: "note", Type: "", Value: "it's"},
+		{Name: "active", Type: "boolean", Format: "Y/N"},
+	}
+	pp := models.Postprocess{Schema: "public", Table: "events"}
+
+	insert := models.Entity{
+		Config: models.Config{
+			FileName: "events.sql", Format: "sql", RowCount: 3, Seed: "sql",
+			SQL: &models.SQLOptions{BatchSize: 2},
+		},
+		Postprocess: pp,
+		Fields:      fields,
+	}
+	copyMode := models.Entity{
+		Config: models.Config{
+			FileName: "events_copy.sql", Format: "sql", RowCount: 2, Seed: "sql",
+			SQL: &models.SQLOptions{Mode: "copy"},
+		},
+		Postprocess: pp,
+		Fields:      fields,
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{insert, copyMode}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/events.sql")
+	assert.NoError(t, err)
+	script := string(raw)
+	assert.Equal(t, 2, strings.Count(script, `INSERT INTO "public"."events" ("id", "note", "active") VALUES`))
+	assert.Contains(t, script, "(2, 'it''s', ")
+	assert.True(t, strings.HasSuffix(script, ");\n"))
+
+	raw, err = os.ReadFile("output/events_copy.sql")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, `COPY "public"."events" ("id", "note", "active") FROM stdin;`, lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "2\tit's\t"))
+	assert.Equal(t, `\.`, lines[3])
+}
+
+func TestProcessFiles_SQLRequiresTable(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "events.sql", Format: "sql", RowCount: 1},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.Error(t, err)
+	os.RemoveAll("output")
+}
+
+func TestProcessFiles_Fixed(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "bacs.txt", Format: "fixed", RowCount: 3, Seed: "fixed",
+			Header: "HDR", Footer: "TRL",
+		},
+		Fields: []models.Field{
+			{Name: "id", Type: "iterator", Width: 6, Align: "right", PadChar: "0"},
+			{Name: "name", Type: "", Value: "ACME PAYMENTS LTD", Width: 10},
+			{Name: "code", Type: "", Value: "AB", Width: 4, Align: "right"},
+			{Name: "ref", Type: "", Value: "REF-0001", Width: 4, Truncate: "left"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/bacs.txt")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+	assert.Equal(t, []string{
+		"HDR                     ",
+		"000002ACME PAYME  AB0001",
+		"000003ACME PAYME  AB0001",
+		"000004ACME PAYME  AB0001",
+		"TRL                     ",
+	}, lines)
+}
+
+func TestProcessFiles_FixedRequiresWidth(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "bacs.txt", Format: "fixed", RowCount: 1},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.Error(t, err)
+	os.RemoveAll("output")
+}
+
+func TestProcessFiles_Records(t *testing.T) {
+	start := 0
+	entity := models.Entity{
+		Config: models.Config{FileName: "settlement.csv", Delimiter: ",", RowCount: 5, BatchSize: 2, Seed: "records"},
+		Fields: []models.Field{
+			{Name: "type", Type: "", Value: "D"},
+			{Name: "amount", Type: "number", Min: "1", Max: "100", Format: "2"},
+		},
+		Records: []models.Record{
+			{Name: "file_header", Kind: "header", Fields: []models.Field{
+				{Name: "type", Type: "", Value: "H"},
+				{Name: "batches", Type: "aggregate", Function: "batches"},
+			}},
+			{Name: "batch_header", Kind: "batch_header", Fields: []models.Field{
+				{Name: "type", Type: "", Value: "BH"},
+				{Name: "batch", Type: "iterator", Start: &start},
+			}},
+			{Name: "batch_trailer", Kind: "batch_trailer", Fields: []models.Field{
+				{Name: "type", Type: "", Value: "BT"},
+				{Name: "count", Type: "aggregate", Function: "count"},
+				{Name: "total", Type: "aggregate", Function: "sum", Target: "amount"},
+			}},
+			{Name: "file_trailer", Kind: "trailer", Fields: []models.Field{
+				{Name: "type", Type: "", Value: "T"},
+				{Name: "count", Type: "aggregate", Function: "count"},
+				{Name: "total", Type: "aggregate", Function: "sum", Target: "amount"},
+			}},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/settlement.csv")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+
+	var kinds []string
+	for _, line := range lines {
+		kinds = append(kinds, strings.Split(line, ",")[0])
+	}
+	assert.Equal(t, []string{"H", "BH", "D", "D", "BT", "BH", "D", "D", "BT", "BH", "D", "BT", "T"}, kinds)
+	assert.Equal(t, "H,3", lines[0])
+	assert.Equal(t, []string{"BH,1", "BH,2", "BH,3"}, []string{lines[1], lines[5], lines[9]})
+
+	// trailers total the details in their scope
+	fileTotal := decimal.Zero
+	batchTotal := decimal.Zero
+	batchCount := 0
+	for _, line := range lines {
+		cells := strings.Split(line, ",")
+		switch cells[0] {
+		case "D":
+			d := decimal.RequireFromString(cells[1])
+			batchTotal, fileTotal = batchTotal.Add(d), fileTotal.Add(d)
+			batchCount++
+		case "BT":
+			assert.Equal(t, fmt.Sprint(batchCount), cells[1])
+			assert.Equal(t, batchTotal.StringFixed(2), cells[2])
+			batchTotal, batchCount = decimal.Zero, 0
+		case "T":
+			assert.Equal(t, "5", cells[1])
+			assert.Equal(t, fileTotal.StringFixed(2), cells[2])
+		}
+	}
+}
+
+func TestProcessFiles_ControlTotals(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "payments.csv", Delimiter: ",", RowCount: 4, FileCount: 2, Seed: "totals",
+			Header: "H,${row_count},${date:20060102},${file_index}/${file_count}",
+			Footer: "T,${sum:amount},${max:amount:1},${seed}",
+		},
+		Fields: []models.Field{
+			{Name: "amount", Type: "number", Min: "1", Max: "100", Format: "2"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	for i, name := range []string{"output/payments_1.csv", "output/payments_2.csv"} {
+		raw, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+		assert.Len(t, lines, 6)
+
+		assert.Equal(t, fmt.Sprintf("H,4,%s,%d/2", time.Now().Format("20060102"), i+1), lines[0])
+
+		sum, most := decimal.Zero, decimal.Zero
+		for _, line := range lines[1:5] {
+			d := decimal.RequireFromString(line)
+			sum, most = sum.Add(d), decimal.Max(most, d)
+		}
+		assert.Equal(t, fmt.Sprintf("T,%s,%s,totals", sum.StringFixed(2), most.StringFixed(1)), lines[5])
+	}
+}
+
+func TestProcessFiles_ControlTotalsEscape(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "payments.csv", Delimiter: ",", RowCount: 1,
+			Header: "H,$${batch},${row_count}", Footer: "T,$${unknown}",
+		},
+		Fields: []models.Field{{Name: "amount", Type: "", Value: "1"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/payments.csv")
+	assert.NoError(t, err)
+	assert.Equal(t, "H,${batch},1\n1\nT,${unknown}\n", string(raw))
+}
+
+func TestProcessFiles_SpoolsToTempFiles(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "ledger.csv", Delimiter: ",", RowCount: 3, BatchSize: 2, Compression: "gzip",
+			Header: "HDR,static", Footer: "TRL,${row_count}",
+		},
+		Fields: []models.Field{{Name: "type", Type: "", Value: "D"}},
+		Records: []models.Record{
+			{Name: "batch_header", Kind: "batch_header", Fields: []models.Field{
+				{Name: "count", Type: "aggregate", Function: "count"},
+			}},
+		},
+	}
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	f, err := os.Open("output/ledger.csv.gz")
+	assert.NoError(t, err)
+	defer f.Close()
+	r, err := compress.NewReader(f)
+	assert.NoError(t, err)
+	raw, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "HDR,static\n2\nD\nD\n1\nD\nTRL,3\n", string(raw))
+
+	// the spooled rows are removed once the file is written
+	left, err := os.ReadDir(tmp)
+	assert.NoError(t, err)
+	assert.Empty(t, left)
+}
+
+func TestProcessFiles_ControlTotalsUnknownColumn(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "payments.csv", Delimiter: ",", RowCount: 1, Footer: "T,${sum:amnt}"},
+		Fields: []models.Field{{Name: "amount", Type: "number", Min: "1", Max: "100"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.ErrorContains(t, err, `no field named "amnt"`)
+	os.RemoveAll("output")
+}
+
+func TestProcessFiles_XML(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "tx.xml")
+	envelope := filepath.Join(dir, "envelope.xml")
+	assert.NoError(t, os.WriteFile(template, []byte(`<CdtTrfTxInf><EndToEndId>${id}</EndToEndId><Amt Ccy="GBP">${amount}</Amt><Nm>${name}</Nm></CdtTrfTxInf>`), 0o644))
+	assert.NoError(t, os.WriteFile(envelope, []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Document><GrpHdr><NbOfTxs>${row_count}</NbOfTxs><CtrlSum>${sum:amount}</CtrlSum></GrpHdr>
+${rows}</Document>
+`), 0o644))
+
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "pain.xml", Format: "xml", RowCount: 3, Seed: "xml",
+			XML: &models.XMLOptions{Template: template, Envelope: envelope},
+		},
+		Fields: []models.Field{
+			{Name: "id", Type: "iterator"},
+			{Name: "amount", Type: "number", Min: "1", Max: "100", Format: "2"},
+			{Name: "name", Type: "", Value: "Smith & <Sons>"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/pain.xml")
+	assert.NoError(t, err)
+
+	var doc struct {
+		NbOfTxs string `xml:"GrpHdr>NbOfTxs"`
+		CtrlSum string `xml:"GrpHdr>CtrlSum"`
+		Txs     []struct {
+			ID     string `xml:"EndToEndId"`
+			Amount string `xml:"Amt"`
+			Name   string `xml:"Nm"`
+		} `xml:"CdtTrfTxInf"`
+	}
+	assert.NoError(t, xml.Unmarshal(raw, &doc))
+	assert.Equal(t, "3", doc.NbOfTxs)
+	assert.Len(t, doc.Txs, 3)
+
+	sum := decimal.Zero
+	for i, tx := range doc.Txs {
+		assert.Equal(t, fmt.Sprint(i+2), tx.ID)
+		assert.Equal(t, "Smith & <Sons>", tx.Name)
+		sum = sum.Add(decimal.RequireFromString(tx.Amount))
+	}
+	assert.Equal(t, sum.StringFixed(2), doc.CtrlSum)
+}
+
+func TestProcessFiles_XMLMalformedTemplate(t *testing.T) {
+	template := filepath.Join(t.TempDir(), "tx.xml")
+	assert.NoError(t, os.WriteFile(template, []byte(`<Tx><Id>${id}</Tx>`), 0o644))
+
+	entity := models.Entity{
+		Config: models.Config{FileName: "pain.xml", Format: "xml", RowCount: 1, XML: &models.XMLOptions{Template: template}},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.ErrorContains(t, err, "xml template")
+	os.RemoveAll("output")
+}
+
+func TestProcessFiles_XLSX(t *testing.T) {
+	customers := models.Entity{
+		Config: models.Config{FileName: "report.xlsx", Format: "xlsx", Sheet: "Customers", RowCount: 3, Seed: "xlsx"},
+		Fields: []models.Field{
+			{Name: "id", Type: "iterator"},
+			{Name: "name", Type: "", Value: "Ada"},
+		},
+	}
+	payments := models.Entity{
+		Config: models.Config{FileName: "report.xlsx", Format: "xlsx", Sheet: "Payments", RowCount: 2, Seed: "xlsx"},
+		Fields: []models.Field{
+			{Name: "amount", Type: "number", Min: "1", Max: "10", Format: "2"},
+			{Name: "paid_on", Type: "timestamp", Format: "2006-01-02"},
+			{Name: "settled", Type: "boolean"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{customers, payments}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	book, err := excelize.OpenFile("output/report.xlsx")
+	assert.NoError(t, err)
+	defer book.Close()
+	assert.Equal(t, []string{"Customers", "Payments"}, book.GetSheetList())
+
+	rows, err := book.GetRows("Customers")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"id", "name"}, {"2", "Ada"}, {"3", "Ada"}, {"4", "Ada"}}, rows)
+
+	// numbers are stored without a cell type, strings as shared or inline strings
+	typ, err := book.GetCellType("Payments", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, excelize.CellTypeUnset, typ)
+	amount, err := book.GetCellValue("Payments", "A2", excelize.Options{RawCellValue: true})
+	assert.NoError(t, err)
+	_, err = strconv.ParseFloat(amount, 64)
+	assert.NoError(t, err)
+	typ, err = book.GetCellType("Payments", "C2")
+	assert.NoError(t, err)
+	assert.Equal(t, excelize.CellTypeBool, typ)
+
+	paidOn, err := book.GetCellValue("Payments", "B2")
+	assert.NoError(t, err)
+	_, err = time.Parse("2006-01-02", paidOn)
+	assert.NoError(t, err, "dates are formatted as dates")
+
+	panes, err := book.GetPanes("Payments")
+	assert.NoError(t, err)
+	assert.True(t, panes.Freeze)
+	assert.Equal(t, 1, panes.YSplit)
+}
+
+func TestReadFixedWidth_Compressed(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{
+			FileName: "bacs.txt", Format: "fixed", RowCount: 2, Compression: "gzip",
+			Header: "HDR", Footer: "TRL",
+		},
+		Fields: []models.Field{
+			{Name: "id", Type: "iterator", Width: 6, Align: "right", PadChar: "0"},
+			{Name: "code", Type: "", Value: "AB", Width: 4},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	lines, _, header, footer, err := csvgen.ReadFixedWidth("output/bacs.txt.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"000002AB  ", "000003AB  "}, lines)
+	assert.Equal(t, "HDR", header)
+	assert.Equal(t, "TRL", footer)
+}
+
+func TestProcessFiles_Compression(t *testing.T) {
+	for codec, want := range map[string]struct {
+		path  string
+		magic string
+	}{
+		"gzip": {"output/ledger.csv.gz", "\x1f\x8b"},
+		"zstd": {"output/ledger.csv.zst", "\x28\xb5\x2f\xfd"},
+	} {
+		entity := models.Entity{
+			Config: models.Config{FileName: "ledger.csv", Delimiter: ",", IncludeHeaders: true, RowCount: 3, Compression: codec, Footer: "TRL,${row_count}"},
+			Fields: []models.Field{{Name: "id", Type: "iterator"}, {Name: "code", Type: "", Value: "AB"}},
+		}
+
+		err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+		assert.NoError(t, err, codec)
+
+		raw, err := os.ReadFile(want.path)
+		assert.NoError(t, err, codec)
+		assert.True(t, strings.HasPrefix(string(raw), want.magic), "%s output is compressed", codec)
+
+		rows, headers, _, err := csvgen.ReadCSVAsMap(want.path)
+		assert.NoError(t, err, codec)
+		assert.Equal(t, []string{"id", "code"}, headers)
+		assert.Equal(t, []map[string]any{
+			{"id": "2", "code": "AB"}, {"id": "3", "code": "AB"}, {"id": "4", "code": "AB"}, {"id": "TRL", "code": "3"},
+		}, rows, codec)
+		os.RemoveAll("output")
+	}
+}
+
+func TestProcessFiles_CompressionBzip2(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "ledger.csv", Delimiter: ",", RowCount: 1, Compression: "bzip2"},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.ErrorContains(t, err, "bzip2 can be read but not written")
+	os.RemoveAll("output")
+}
+
+func TestProcessFilesTo_Stdout(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "ledger.csv", Delimiter: ",", IncludeHeaders: true, RowCount: 2, Footer: "TRL,${row_count}"},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}, {Name: "code", Type: "", Value: "AB"}},
+	}
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	err = csvgen.ProcessFilesTo(models.FileConfig{Files: []models.Entity{entity}}, "-", false, false)
+	os.Stdout = stdout
+	w.Close()
+	assert.NoError(t, err)
+
+	out, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,code\n2,AB\n3,AB\nTRL,2\n", string(out))
+	_, err = os.Stat("output")
+	assert.True(t, os.IsNotExist(err), "nothing is written to output/")
+}
+
+func TestProcessFilesTo_StdoutRejects(t *testing.T) {
+	upload := models.Entity{
+		Config:      models.Config{FileName: "-", Delimiter: ",", RowCount: 1},
+		Fields:      []models.Field{{Name: "id", Type: "iterator"}},
+		Postprocess: models.Postprocess{Enabled: true, Location: "s3://bucket/path"},
+	}
+	err := csvgen.ProcessFilesTo(models.FileConfig{Files: []models.Entity{upload}}, "output", false, false)
+	assert.ErrorContains(t, err, "can't be post-processed")
+
+	// encryption runs on dry runs too, so it is rejected rather than skipped
+	encrypted := upload
+	encrypted.Postprocess = models.Postprocess{Enabled: true, Encrypt: &models.EncryptOptions{Recipients: []string{"key.asc"}}}
+	err = csvgen.ProcessFilesTo(models.FileConfig{Files: []models.Entity{encrypted}}, "output", false, true)
+	assert.ErrorContains(t, err, "can't be encrypted")
+
+	parquet := models.Entity{
+		Config: models.Config{FileName: "ledger.parquet", Format: "parquet", RowCount: 1, FileCount: 2},
+		Fields: []models.Field{{Name: "id", Type: "iterator"}},
+	}
+	err = csvgen.ProcessFilesTo(models.FileConfig{Files: []models.Entity{parquet}}, "-", false, false)
+	assert.ErrorContains(t, err, "only one file can be written to stdout")
+}
+
+func TestProcessFiles_Reflection(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "reflect.csv", Delimiter: ",", RowCount: 1, Seed: "reflect"},
+		Fields: []models.Field{
+			{Name: "amount", Type: "", Value: "12.50"},
+			{Name: "inverse", Type: "reflection", Target: "amount", Modifier: "-1"},
+			{Name: "booked", Type: "timestamp", Format: "2006-01-02"},
+			{Name: "booked_copy", Type: "reflection", Target: "booked", Format: "2006-01-02"},
+			{Name: "pan", Type: "card", Scheme: "visa"},
+			{Name: "masked", Type: "reflection", Target: "pan", Format: "masked"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	raw, err := os.ReadFile("output/reflect.csv")
+	assert.NoError(t, err)
+	cells := strings.Split(strings.TrimSpace(string(raw)), ",")
+	assert.Len(t, cells, 6)
+	assert.Equal(t, "-12.50", cells[1], "the modifier is applied once")
+	assert.Equal(t, cells[2], cells[3], "format only derives card values")
+	assert.Equal(t, fakers.MaskPAN(cells[4]), cells[5])
+}
+
+func TestProcessFiles_ReflectionTypes(t *testing.T) {
+	entity := models.Entity{
+		Config: models.Config{FileName: "reflect.avro", Format: "avro", RowCount: 2, Seed: "reflect"},
+		Fields: []models.Field{
+			{Name: "amount", Type: "number", Min: "1", Max: "10", Format: "2"},
+			{Name: "inverse", Type: "reflection", Target: "amount", Modifier: "-1"},
+			{Name: "seq", Type: "iterator"},
+			{Name: "scaled", Type: "reflection", Target: "seq", Modifier: "3"},
+			{Name: "booked", Type: "timestamp", Format: "2006-01-02"},
+			{Name: "booked_copy", Type: "reflection", Target: "booked", Format: "2006-01-02"},
+			{Name: "pan", Type: "card", Scheme: "visa"},
+			{Name: "masked", Type: "reflection", Target: "pan", Format: "masked"},
+		},
+	}
+
+	err := csvgen.ProcessFiles(models.FileConfig{Files: []models.Entity{entity}}, false, true)
+	assert.NoError(t, err)
+	defer os.RemoveAll("output")
+
+	f, err := os.Open("output/reflect.avro")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	r, err := goavro.NewOCFReader(f)
+	assert.NoError(t, err)
+
+	rows := 0
+	for r.Scan() {
+		datum, err := r.Read()
+		assert.NoError(t, err)
+		row := datum.(map[string]any)
+
+		// a modified decimal keeps its scale and a modified long becomes a double
+		amount, inverse := row["amount"].(*big.Rat), row["inverse"].(*big.Rat)
+		assert.Equal(t, new(big.Rat).Neg(amount), inverse)
+		assert.Equal(t, float64(row["seq"].(int64)*3), row["scaled"])
+		assert.Equal(t, row["booked"], row["booked_copy"])
+		assert.IsType(t, time.Time{}, row["booked_copy"])
+		assert.Equal(t, fakers.MaskPAN(row["pan"].(string)), row["masked"])
+		rows++
+	}
+	assert.Equal(t, 2, rows)
+}
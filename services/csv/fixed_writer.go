@@ -0,0 +1,157 @@
+package csv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kream404/spoof/models"
+)
+
+type fixedColumn struct {
+	name     string
+	width    int
+	right    bool
+	pad      string
+	truncate string
+}
+
+// fixedRowWriter writes each row as one fixed-width record, columns sized by the fields' width.
+type fixedRowWriter struct {
+	w       *bufio.Writer
+	columns []fixedColumn
+	width   int // record width: the sum of the column widths
+	headers bool
+}
+
+func newFixedRowWriter(file models.Entity, w io.Writer) (*fixedRowWriter, error) {
+	f := &fixedRowWriter{w: bufio.NewWriter(w), headers: file.Config.IncludeHeaders}
+	for _, field := range file.Fields {
+		if field.Skip {
+			continue
+		}
+		col, err := fixedColumnFor(field)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		f.columns = append(f.columns, col)
+		f.width += col.width
+	}
+
+	for _, line := range []string{file.Config.Header, file.Config.Footer} {
+		// lines with tokens are checked once they are rendered
+		if hasControlTokens(line) {
+			continue
+		}
+		line, _ = renderControlLine(line, controlTotals{})
+		if _, err := f.Record(line); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func fixedColumnFor(f models.Field) (fixedColumn, error) {
+	col := fixedColumn{name: f.Name, width: f.Width, pad: " ", truncate: "right"}
+	if f.Width <= 0 {
+		return col, fmt.Errorf("fixed format requires a width")
+	}
+
+	switch strings.ToLower(f.Align) {
+	case "", "left":
+	case "right":
+		col.right = true
+	default:
+		return col, fmt.Errorf("invalid align %q (expected left or right)", f.Align)
+	}
+
+	if f.PadChar != "" {
+		if utf8.RuneCountInString(f.PadChar) != 1 {
+			return col, fmt.Errorf("pad_char must be a single character, got %q", f.PadChar)
+		}
+		col.pad = f.PadChar
+	}
+
+	switch t := strings.ToLower(f.Truncate); t {
+	case "", "right":
+	case "left", "error":
+		col.truncate = t
+	default:
+		return col, fmt.Errorf("invalid truncate %q (expected right, left or error)", f.Truncate)
+	}
+	return col, nil
+}
+
+// Record pads a header or footer line with spaces to the record width.
+func (f *fixedRowWriter) Record(line string) (string, error) {
+	n := utf8.RuneCountInString(line)
+	if n > f.width {
+		return "", fmt.Errorf("header/footer %q is %d characters, longer than the %d character record", line, n, f.width)
+	}
+	return line + strings.Repeat(" ", f.width-n), nil
+}
+
+func (f *fixedRowWriter) WriteHeader(names []string) error {
+	if !f.headers {
+		return nil
+	}
+	var b strings.Builder
+	for i, c := range f.columns {
+		c.truncate, c.pad = "right", " "
+		cell, _ := c.format(names[i])
+		b.WriteString(cell)
+	}
+	b.WriteByte('\n')
+	_, err := f.w.WriteString(b.String())
+	return err
+}
+
+func (f *fixedRowWriter) WriteRow(_ []string, values []models.Value) error {
+	var b strings.Builder
+	for i, v := range values {
+		c := f.columns[i]
+		if v.IsNull() {
+			// null is a blank column, even when the pad is zeroes
+			b.WriteString(strings.Repeat(" ", c.width))
+			continue
+		}
+		cell, err := c.format(v.Text)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", c.name, err)
+		}
+		b.WriteString(cell)
+	}
+	b.WriteByte('\n')
+	_, err := f.w.WriteString(b.String())
+	return err
+}
+
+func (f *fixedRowWriter) Flush() error { return f.w.Flush() }
+
+// format pads s to the column width, or truncates it when it is too long.
+func (c fixedColumn) format(s string) (string, error) {
+	s = strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+	runes := []rune(s)
+	if n := len(runes); n > c.width {
+		switch c.truncate {
+		case "error":
+			return "", fmt.Errorf("value %q is longer than width %d", s, c.width)
+		case "left":
+			return string(runes[n-c.width:]), nil
+		default:
+			return string(runes[:c.width]), nil
+		}
+	}
+
+	fill := strings.Repeat(c.pad, c.width-len(runes))
+	if !c.right {
+		return s + fill, nil
+	}
+	// zero padding goes after the sign of a negative number: -0000012
+	if c.pad == "0" && strings.HasPrefix(s, "-") {
+		return "-" + fill + s[1:], nil
+	}
+	return fill + s, nil
+}
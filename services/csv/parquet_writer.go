@@ -0,0 +1,113 @@
+package csv
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/parquet"
+)
+
+// parquetRowWriter writes rows to a parquet file with a schema derived from the field types.
+type parquetRowWriter struct {
+	w       *parquet.Writer
+	columns []column
+}
+
+func newParquetRowWriter(file models.Entity, w io.Writer) (*parquetRowWriter, error) {
+	var opts parquet.Options
+	if p := file.Config.Parquet; p != nil {
+		opts = parquet.Options{RowGroupSize: p.RowGroupSize, Compression: p.Compression}
+	}
+
+	columns, err := outputColumns(file.Fields)
+	if err != nil {
+		return nil, err
+	}
+	schema := make([]parquet.Column, len(columns))
+	for i, c := range columns {
+		schema[i] = parquetColumn(c)
+	}
+
+	pw, err := parquet.NewWriter(w, schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetRowWriter{w: pw, columns: columns}, nil
+}
+
+func (p *parquetRowWriter) WriteHeader([]string) error { return nil }
+
+func (p *parquetRowWriter) WriteRow(_ []string, values []models.Value) error {
+	row := make([]any, len(values))
+	for i, v := range values {
+		pv, err := parquetValue(p.columns[i], v)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", p.columns[i].Field.Name, err)
+		}
+		row[i] = pv
+	}
+	return p.w.Write(row)
+}
+
+func (p *parquetRowWriter) Flush() error { return p.w.Close() }
+
+func parquetColumn(c column) parquet.Column {
+	col := parquet.Column{Name: c.Field.Name}
+	switch c.Type {
+	case colLong:
+		col.Type = parquet.Int64
+	case colDouble:
+		col.Type = parquet.Double
+	case colDecimal:
+		col.Type, col.Logical = parquet.Int64, parquet.Decimal
+		col.Scale, col.Precision = c.Scale, decimalPrecision
+	case colTimestamp:
+		col.Type, col.Logical = parquet.Int64, parquet.Timestamp
+	case colBool:
+		col.Type = parquet.Boolean
+	case colUUID:
+		col.Type, col.Logical, col.Length = parquet.FixedLenByteArray, parquet.UUID, 16
+	case colJSON:
+		col.Type, col.Logical = parquet.ByteArray, parquet.JSON
+	default:
+		col.Type, col.Logical = parquet.ByteArray, parquet.String
+	}
+	return col
+}
+
+// parquetValue converts a value to the column's physical type.
+func parquetValue(c column, v models.Value) (any, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+
+	switch c.Type {
+	case colLong:
+		return longValue(v)
+	case colDouble:
+		return doubleValue(v)
+	case colDecimal:
+		d, err := scaledDecimal(v, c.Scale)
+		if err != nil {
+			return nil, err
+		}
+		return d.IntPart(), nil
+	case colTimestamp:
+		t, err := timestampValue(v, c.Field)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnixMicro(), nil
+	case colBool:
+		return boolValue(v)
+	case colUUID:
+		u, err := uuidValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return u[:], nil
+	default:
+		return v.Text, nil
+	}
+}
@@ -0,0 +1,190 @@
+package csv
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/evaluator"
+)
+
+// Record kinds, in the order they are written around the detail records.
+const (
+	RecordHeader       = "header"
+	RecordBatchHeader  = "batch_header"
+	RecordBatchTrailer = "batch_trailer"
+	RecordTrailer      = "trailer"
+)
+
+type recordType struct {
+	record  models.Record
+	entity  models.Entity // the entity with the record's fields, for its row writer
+	names   []string
+	sources fieldCache // loaded on first use
+}
+
+// recordLayout writes a multi-record file: the header records, then for each
+// batch its batch_header records, detail records and batch_trailer records,
+// then the trailer records. Headers are written after their details are
+// generated, so headers and trailers can both read the totals; until then the
+// details are spooled to temp files.
+type recordLayout struct {
+	kinds     map[string][]*recordType
+	batchSize int
+	rng       *rand.Rand
+
+	out    io.Writer
+	detail RowWriter // writes to batch
+	batch  *spool    // details of the open batch
+	body   *spool    // completed batches
+
+	batchAgg *evaluator.Aggregate
+	fileAgg  *evaluator.Aggregate
+}
+
+func newRecordLayout(file models.Entity, out io.Writer, rng *rand.Rand) (*recordLayout, error) {
+	switch format := outputFormat(file.Config); format {
+	case FormatCSV, FormatJSONL, FormatFixed:
+	default:
+		return nil, fmt.Errorf("records are not supported by %s (expected csv, jsonl or fixed)", format)
+	}
+	if file.Config.IncludeHeaders {
+		return nil, fmt.Errorf("include_headers is not supported with records; add a header record instead")
+	}
+
+	l := &recordLayout{
+		kinds:     make(map[string][]*recordType),
+		batchSize: file.Config.BatchSize,
+		rng:       rng,
+		out:       out,
+		batchAgg:  evaluator.NewAggregate(),
+		fileAgg:   evaluator.NewAggregate(),
+	}
+	for i, r := range file.Records {
+		kind := strings.ToLower(strings.TrimSpace(r.Kind))
+		switch kind {
+		case RecordHeader, RecordBatchHeader, RecordBatchTrailer, RecordTrailer:
+		default:
+			return nil, fmt.Errorf("record %d (%s): invalid kind %q (expected header, batch_header, batch_trailer or trailer)", i+1, r.Name, r.Kind)
+		}
+
+		sub := file
+		sub.Fields, sub.Records = r.Fields, nil
+		sub.Config.Header, sub.Config.Footer = "", ""
+		if _, err := newRowWriter(sub, io.Discard); err != nil {
+			return nil, fmt.Errorf("record %s: %w", r.Name, err)
+		}
+
+		rt := &recordType{record: r, entity: sub}
+		for _, f := range r.Fields {
+			if !f.Skip {
+				rt.names = append(rt.names, f.Name)
+			}
+		}
+		l.kinds[kind] = append(l.kinds[kind], rt)
+	}
+	return l, nil
+}
+
+// open creates the temp files the details are spooled to; release removes them.
+func (l *recordLayout) open() error {
+	var err error
+	if l.batch, err = newSpool(); err != nil {
+		return err
+	}
+	if l.body, err = newSpool(); err != nil {
+		l.batch.Close()
+		return err
+	}
+	return nil
+}
+
+func (l *recordLayout) release() error {
+	err := l.batch.Close()
+	if berr := l.body.Close(); err == nil {
+		err = berr
+	}
+	return err
+}
+
+// add totals a written detail record and closes the batch when it is full.
+func (l *recordLayout) add(generated map[string]string) error {
+	l.batchAgg.Add(generated)
+	l.fileAgg.Add(generated)
+	if l.batchSize > 0 && l.batchAgg.Count >= l.batchSize {
+		return l.endBatch()
+	}
+	return nil
+}
+
+func (l *recordLayout) endBatch() error {
+	if err := l.detail.Flush(); err != nil {
+		return err
+	}
+	l.fileAgg.Batches++
+	l.batchAgg.Batches = 1
+	index := l.fileAgg.Batches
+
+	if err := l.writeRecords(l.body, RecordBatchHeader, l.batchAgg, index); err != nil {
+		return err
+	}
+	if _, err := l.batch.WriteTo(l.body); err != nil {
+		return err
+	}
+	if err := l.writeRecords(l.body, RecordBatchTrailer, l.batchAgg, index); err != nil {
+		return err
+	}
+
+	if err := l.batch.Reset(); err != nil {
+		return err
+	}
+	l.batchAgg = evaluator.NewAggregate()
+	return nil
+}
+
+// close ends the last batch and writes the file with its header and trailer records.
+func (l *recordLayout) close() error {
+	if err := l.detail.Flush(); err != nil {
+		return err
+	}
+	if l.batchAgg.Count > 0 || l.fileAgg.Batches == 0 {
+		if err := l.endBatch(); err != nil {
+			return err
+		}
+	}
+
+	if err := l.writeRecords(l.out, RecordHeader, l.fileAgg, 1); err != nil {
+		return err
+	}
+	if _, err := l.body.WriteTo(l.out); err != nil {
+		return err
+	}
+	return l.writeRecords(l.out, RecordTrailer, l.fileAgg, 1)
+}
+
+func (l *recordLayout) writeRecords(w io.Writer, kind string, agg *evaluator.Aggregate, index int) error {
+	for _, rt := range l.kinds[kind] {
+		if rt.sources == nil {
+			rt.sources = preloadFieldSources(rt.record.Fields)
+		}
+
+		values, _, err := evaluator.GenerateRecord(rt.entity, rt.record.Fields, agg, nil, map[string][]map[string]any(rt.sources), index, 0, l.rng, nil)
+		if err != nil {
+			return fmt.Errorf("record %s: %w", rt.record.Name, err)
+		}
+
+		rw, err := newRowWriter(rt.entity, w)
+		if err != nil {
+			return err
+		}
+		if err := rw.WriteRow(rt.names, values); err != nil {
+			return fmt.Errorf("record %s: %w", rt.record.Name, err)
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
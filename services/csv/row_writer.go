@@ -0,0 +1,170 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	jsonstd "encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/kream404/spoof/models"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+	FormatAvro    = "avro"
+	FormatSQL     = "sql"
+	FormatFixed   = "fixed"
+	FormatXML     = "xml"
+	FormatXLSX    = "xlsx"
+)
+
+// RowWriter writes generated rows in one output format.
+type RowWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(names []string, values []models.Value) error
+	Flush() error
+}
+
+// outputFormat returns the entity's config.format, defaulting to csv.
+func outputFormat(config models.Config) string {
+	f := strings.ToLower(strings.TrimSpace(config.Format))
+	if f == "" {
+		return FormatCSV
+	}
+	return f
+}
+
+// textFormat reports whether the format is line-based text that header and footer lines can wrap.
+func textFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSONL || format == FormatSQL || format == FormatFixed
+}
+
+func newRowWriter(file models.Entity, w io.Writer) (RowWriter, error) {
+	config := file.Config
+	switch format := outputFormat(config); format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if d := config.Delimiter; len(d) > 0 && d[0] != 0 {
+			cw.Comma = rune(d[0])
+		}
+		return &csvRowWriter{w: cw, headers: config.IncludeHeaders}, nil
+	case FormatJSONL:
+		return &jsonlRowWriter{w: w}, nil
+	case FormatParquet:
+		return newParquetRowWriter(file, w)
+	case FormatAvro:
+		return newAvroRowWriter(file, w)
+	case FormatSQL:
+		return newSQLRowWriter(file, w)
+	case FormatFixed:
+		return newFixedRowWriter(file, w)
+	case FormatXML:
+		return newXMLRowWriter(file, w)
+	case FormatXLSX:
+		return newXLSXRowWriter(file, w)
+	default:
+		return nil, fmt.Errorf("unsupported format %q (expected csv, jsonl, parquet, avro, sql, fixed, xml or xlsx)", format)
+	}
+}
+
+//
+// ───────────────────────── CSV ────────────────────────────
+//
+
+type csvRowWriter struct {
+	w       *csv.Writer
+	headers bool
+}
+
+func (c *csvRowWriter) WriteHeader(names []string) error {
+	if !c.headers {
+		return nil
+	}
+	return c.w.Write(names)
+}
+
+func (c *csvRowWriter) WriteRow(_ []string, values []models.Value) error {
+	return c.w.Write(csvRecord(values))
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvRecord formats a row for csv: each value's text, with null written as an empty cell.
+func csvRecord(values []models.Value) []string {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = v.String()
+	}
+	return record
+}
+
+//
+// ───────────────────────── JSON LINES ──────────────────────────
+//
+
+// jsonlRowWriter writes one JSON object per row, keys in field order.
+type jsonlRowWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (j *jsonlRowWriter) WriteHeader([]string) error { return nil }
+
+func (j *jsonlRowWriter) WriteRow(names []string, values []models.Value) error {
+	j.buf.Reset()
+	j.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		key, _ := jsonstd.Marshal(names[i])
+		j.buf.Write(key)
+		j.buf.WriteByte(':')
+		raw, err := jsonValue(v)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", names[i], err)
+		}
+		j.buf.Write(raw)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonlRowWriter) Flush() error { return nil }
+
+// jsonValue encodes a value with its JSON type. Decimals keep their places (12.50),
+// nested json fields are embedded, and timestamps are strings in the field's format.
+func jsonValue(v models.Value) ([]byte, error) {
+	switch v.Kind {
+	case models.KindNull:
+		return []byte("null"), nil
+	case models.KindInt, models.KindDecimal:
+		return []byte(v.Text), nil
+	case models.KindFloat:
+		if math.IsNaN(v.Float) || math.IsInf(v.Float, 0) {
+			return []byte("null"), nil
+		}
+		return []byte(v.Text), nil
+	case models.KindBool:
+		if v.Bool {
+			return []byte("true"), nil
+		}
+		return []byte("false"), nil
+	case models.KindJSON:
+		if jsonstd.Valid([]byte(v.Str)) {
+			return []byte(v.Str), nil
+		}
+		return jsonstd.Marshal(v.Str)
+	default:
+		return jsonstd.Marshal(v.Text)
+	}
+}
@@ -0,0 +1,224 @@
+package csv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/kream404/spoof/models"
+)
+
+// decimalPrecision is the most digits an int64 decimal column holds.
+const decimalPrecision = 18
+
+// columnType is the type of an output column in typed formats (parquet, avro).
+type columnType int
+
+const (
+	colString columnType = iota
+	colLong
+	colDouble
+	colDecimal
+	colTimestamp
+	colBool
+	colUUID
+	colJSON
+)
+
+type column struct {
+	Field    models.Field
+	Type     columnType
+	Scale    int32 // colDecimal
+	Nullable bool  // the field has a null_rate
+}
+
+// outputColumns returns the typed columns for the fields that are written.
+func outputColumns(fields []models.Field) ([]column, error) {
+	var columns []column
+	for _, f := range fields {
+		if f.Skip {
+			continue
+		}
+		col, err := columnFor(f, fields)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// columnFor derives a column type from the field: number is a long, double or
+// decimal depending on its format, timestamp, boolean, uuid and json keep their
+// type and everything else is a string.
+func columnFor(f models.Field, fields []models.Field) (column, error) {
+	col := column{Field: f, Nullable: f.NullRate != nil && *f.NullRate > 0}
+
+	switch f.Type {
+	case "iterator":
+		col.Type = colLong
+	case "number":
+		switch {
+		case f.Length > 0 && f.Length <= decimalPrecision:
+			col.Type = colLong
+		case f.Length > 0:
+			col.Type = colString
+		case strings.TrimSpace(f.Format) == "":
+			col.Type = colDouble
+		default:
+			scale, err := strconv.Atoi(f.Format)
+			if err != nil || scale < 0 || scale > decimalPrecision {
+				return col, fmt.Errorf("field %s: invalid number format %q", f.Name, f.Format)
+			}
+			col.Type, col.Scale = colDecimal, int32(scale)
+			if scale == 0 {
+				col.Type = colLong
+			}
+		}
+	case "timestamp":
+		col.Type = colTimestamp
+	case "boolean":
+		col.Type = colBool
+	case "uuid":
+		col.Type = colUUID
+	case "json":
+		col.Type = colJSON
+	case "reflection":
+		// a copy keeps the target's type; card formats derive text from a
+		// card, which is already a string
+		col.Type = colString
+		for _, target := range fields {
+			if target.Name == f.Target && target.Type != "reflection" {
+				tc, err := columnFor(target, fields)
+				if err != nil {
+					return col, err
+				}
+				col.Type, col.Scale, col.Nullable = tc.Type, tc.Scale, tc.Nullable || col.Nullable
+				break
+			}
+		}
+	default:
+		col.Type = colString
+	}
+
+	// a modifier multiplies the value as a decimal rounded to the places it
+	// had: a decimal keeps its scale, a long is written as a double so a large
+	// multiplier can't overflow it, and anything that isn't a number is text
+	if f.Modifier != "" {
+		switch col.Type {
+		case colLong:
+			col.Type = colDouble
+		case colDouble, colDecimal:
+		default:
+			col.Type, col.Scale = colString, 0
+		}
+	}
+	return col, nil
+}
+
+//
+// Value conversion. Values that arrive as text (seeded or injected from a
+// source) are parsed into the column type.
+//
+
+func longValue(v models.Value) (int64, error) {
+	if v.Kind == models.KindInt {
+		return v.Int, nil
+	}
+	d, err := decimalValue(v)
+	if err != nil {
+		return 0, err
+	}
+	if !d.IsInteger() {
+		return 0, fmt.Errorf("%s is not an integer", v.Text)
+	}
+	return d.IntPart(), nil
+}
+
+func doubleValue(v models.Value) (float64, error) {
+	switch v.Kind {
+	case models.KindFloat:
+		return v.Float, nil
+	case models.KindInt:
+		return float64(v.Int), nil
+	case models.KindDecimal:
+		return v.Decimal.InexactFloat64(), nil
+	}
+	f, err := strconv.ParseFloat(v.Text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", v.Text)
+	}
+	return f, nil
+}
+
+// scaledDecimal converts v to a decimal column with scale, as the unscaled
+// integer v * 10^scale. Values that need more than decimalPrecision digits, and
+// so may not fit an int64, are an error.
+func scaledDecimal(v models.Value, scale int32) (decimal.Decimal, error) {
+	d, err := decimalValue(v)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	unscaled := d.Shift(scale).Round(0)
+	if unscaled.Abs().GreaterThanOrEqual(maxUnscaled) {
+		return decimal.Decimal{}, fmt.Errorf("%s does not fit decimal(%d,%d)", d.String(), decimalPrecision, scale)
+	}
+	return unscaled, nil
+}
+
+var maxUnscaled = decimal.New(1, decimalPrecision)
+
+func decimalValue(v models.Value) (decimal.Decimal, error) {
+	switch v.Kind {
+	case models.KindDecimal:
+		return v.Decimal, nil
+	case models.KindInt:
+		return decimal.NewFromInt(v.Int), nil
+	case models.KindFloat:
+		return decimal.NewFromFloat(v.Float), nil
+	}
+	d, err := decimal.NewFromString(v.Text)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("invalid number %q", v.Text)
+	}
+	return d, nil
+}
+
+// timestampValue parses text with the field's format, or RFC3339 without one.
+func timestampValue(v models.Value, f models.Field) (time.Time, error) {
+	if v.Kind == models.KindTimestamp {
+		return v.Time, nil
+	}
+	layout := f.Format
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	t, err := time.Parse(layout, v.Text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", v.Text, err)
+	}
+	return t, nil
+}
+
+func boolValue(v models.Value) (bool, error) {
+	if v.Kind == models.KindBool {
+		return v.Bool, nil
+	}
+	b, err := strconv.ParseBool(v.Text)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean %q", v.Text)
+	}
+	return b, nil
+}
+
+func uuidValue(v models.Value) (uuid.UUID, error) {
+	u, err := uuid.Parse(v.Text)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("invalid uuid %q: %w", v.Text, err)
+	}
+	return u, nil
+}
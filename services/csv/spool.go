@@ -0,0 +1,64 @@
+package csv
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// spool buffers part of a file in a temp file, for output that can only be
+// written once the rows after it have been generated (control totals in a
+// header, batch headers, the xml envelope). Rows are not held in memory.
+type spool struct {
+	f *os.File
+	w *bufio.Writer
+}
+
+func newSpool() (*spool, error) {
+	f, err := os.CreateTemp("", "spoof-*")
+	if err != nil {
+		return nil, err
+	}
+	return &spool{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (s *spool) Write(p []byte) (int, error) { return s.w.Write(p) }
+
+// Reader reads back what was written. Writing again appends after it.
+func (s *spool) Reader() (io.Reader, error) {
+	if err := s.w.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := s.f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return s.f, nil
+}
+
+// WriteTo copies what was written to w.
+func (s *spool) WriteTo(w io.Writer) (int64, error) {
+	r, err := s.Reader()
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+// Reset discards what was written.
+func (s *spool) Reset() error {
+	s.w.Reset(s.f)
+	if err := s.f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := s.f.Seek(0, io.SeekStart)
+	return err
+}
+
+// Close removes the temp file.
+func (s *spool) Close() error {
+	err := s.f.Close()
+	if rerr := os.Remove(s.f.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+package csv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/database"
+)
+
+const defaultSQLBatchSize = 500
+
+// sqlRowWriter writes rows as batched multi-row INSERT statements, or a Postgres COPY block.
+type sqlRowWriter struct {
+	w       *bufio.Writer
+	dialect database.Dialect
+	copy    bool
+	table   string
+	batch   int
+	pending []string
+	prefix  string
+}
+
+func newSQLRowWriter(file models.Entity, w io.Writer) (*sqlRowWriter, error) {
+	pp := file.Postprocess
+	if strings.TrimSpace(pp.Table) == "" {
+		return nil, fmt.Errorf("sql format requires postprocess.table")
+	}
+
+	var opts models.SQLOptions
+	if file.Config.SQL != nil {
+		opts = *file.Config.SQL
+	}
+	dialect, err := database.ParseDialect(opts.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sqlRowWriter{
+		w:       bufio.NewWriter(w),
+		dialect: dialect,
+		table:   database.QualifiedTable(dialect, pp.Schema, pp.Table),
+		batch:   opts.BatchSize,
+	}
+	switch strings.ToLower(opts.Mode) {
+	case "", "insert":
+	case "copy":
+		if dialect != database.Postgres {
+			return nil, fmt.Errorf("sql mode copy is only supported by postgres")
+		}
+		s.copy = true
+	default:
+		return nil, fmt.Errorf("unsupported sql mode %q (expected insert or copy)", opts.Mode)
+	}
+	if s.batch <= 0 {
+		s.batch = pp.BatchSize
+	}
+	if s.batch <= 0 {
+		s.batch = defaultSQLBatchSize
+	}
+	return s, nil
+}
+
+func (s *sqlRowWriter) WriteHeader(names []string) error {
+	cols := make([]string, len(names))
+	for i, n := range names {
+		cols[i] = database.QuoteIdent(s.dialect, n)
+	}
+	if s.copy {
+		_, err := fmt.Fprintf(s.w, "COPY %s (%s) FROM stdin;\n", s.table, strings.Join(cols, ", "))
+		return err
+	}
+	s.prefix = fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", s.table, strings.Join(cols, ", "))
+	return nil
+}
+
+func (s *sqlRowWriter) WriteRow(_ []string, values []models.Value) error {
+	if s.copy {
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = copyValue(v)
+		}
+		_, err := s.w.WriteString(strings.Join(cells, "\t") + "\n")
+		return err
+	}
+
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = sqlLiteral(s.dialect, v)
+	}
+	s.pending = append(s.pending, "("+strings.Join(cells, ", ")+")")
+	if len(s.pending) >= s.batch {
+		return s.flushBatch()
+	}
+	return nil
+}
+
+func (s *sqlRowWriter) flushBatch() error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	_, err := s.w.WriteString(s.prefix + strings.Join(s.pending, ",\n") + ";\n")
+	s.pending = s.pending[:0]
+	return err
+}
+
+func (s *sqlRowWriter) Flush() error {
+	if s.copy {
+		if _, err := s.w.WriteString("\\.\n"); err != nil {
+			return err
+		}
+	} else if err := s.flushBatch(); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// sqlLiteral renders a value as a literal: numbers and booleans unquoted, everything else quoted.
+func sqlLiteral(d database.Dialect, v models.Value) string {
+	switch v.Kind {
+	case models.KindNull:
+		return "NULL"
+	case models.KindInt, models.KindDecimal:
+		return v.Text
+	case models.KindFloat:
+		if math.IsNaN(v.Float) || math.IsInf(v.Float, 0) {
+			return "NULL"
+		}
+		return v.Text
+	case models.KindBool:
+		switch {
+		case d == database.SQLite && v.Bool:
+			return "1"
+		case d == database.SQLite:
+			return "0"
+		case v.Bool:
+			return "TRUE"
+		default:
+			return "FALSE"
+		}
+	case models.KindTimestamp:
+		// MySQL rejects RFC3339, so unformatted timestamps use its DATETIME layout
+		if v.Layout == "" && d == database.MySQL {
+			return database.QuoteLiteral(d, v.Time.UTC().Format("2006-01-02 15:04:05.999999"))
+		}
+		return database.QuoteLiteral(d, v.Text)
+	default:
+		return database.QuoteLiteral(d, v.Text)
+	}
+}
+
+// copyValue renders a value for the COPY text format, where \N is null.
+func copyValue(v models.Value) string {
+	switch v.Kind {
+	case models.KindNull:
+		return `\N`
+	case models.KindBool:
+		if v.Bool {
+			return "t"
+		}
+		return "f"
+	case models.KindTimestamp:
+		if v.Layout == "" {
+			return v.Time.Format(time.RFC3339Nano)
+		}
+	}
+	return database.CopyEscape(v.Text)
+}
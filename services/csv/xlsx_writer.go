@@ -0,0 +1,181 @@
+package csv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/kream404/spoof/models"
+)
+
+// workbooks holds the xlsx workbooks written in this run by output path, so
+// entities with the same file_name are written as sheets of one workbook.
+type workbooks map[string]*excelize.File
+
+// xlsxRowWriter writes rows to one sheet of a workbook, with typed cells, a
+// bold header row and the header frozen. Flush writes the whole workbook.
+type xlsxRowWriter struct {
+	w       io.Writer
+	book    *excelize.File
+	sheet   string
+	named   bool // sheet was set in the config
+	columns []column
+
+	sw     *excelize.StreamWriter
+	row    int
+	styles []int // per column
+}
+
+func newXLSXRowWriter(file models.Entity, w io.Writer) (*xlsxRowWriter, error) {
+	columns, err := outputColumns(file.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxRowWriter{w: w, book: excelize.NewFile(), sheet: "Sheet1", columns: columns}
+	if s := strings.TrimSpace(file.Config.Sheet); s != "" {
+		x.sheet, x.named = s, true
+		if err := x.book.SetSheetName("Sheet1", s); err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", s, err)
+		}
+	}
+	return x, nil
+}
+
+// join adds this writer's sheet to the workbook already written at path in this
+// run, or registers its own workbook there.
+func (x *xlsxRowWriter) join(books workbooks, path string) error {
+	book, ok := books[path]
+	if !ok {
+		books[path] = x.book
+		return nil
+	}
+
+	if !x.named {
+		x.sheet = fmt.Sprintf("Sheet%d", len(book.GetSheetList())+1)
+	}
+	if idx, _ := book.GetSheetIndex(x.sheet); idx != -1 {
+		return fmt.Errorf("sheet %q is already in %s; set a different config.sheet", x.sheet, path)
+	}
+	if _, err := book.NewSheet(x.sheet); err != nil {
+		return fmt.Errorf("sheet %q: %w", x.sheet, err)
+	}
+	x.book = book
+	return nil
+}
+
+func (x *xlsxRowWriter) WriteHeader(names []string) error {
+	sw, err := x.book.NewStreamWriter(x.sheet)
+	if err != nil {
+		return err
+	}
+	x.sw = sw
+
+	if err := sw.SetPanes(&excelize.Panes{
+		Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+	}); err != nil {
+		return err
+	}
+
+	bold, err := x.book.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	header := make([]any, len(names))
+	for i, n := range names {
+		header[i] = excelize.Cell{StyleID: bold, Value: n}
+		if err := sw.SetColWidth(i+1, i+1, float64(max(len(n)+2, 12))); err != nil {
+			return err
+		}
+	}
+
+	x.styles = make([]int, len(x.columns))
+	for i, c := range x.columns {
+		if x.styles[i], err = x.cellStyle(c); err != nil {
+			return err
+		}
+	}
+
+	x.row = 1
+	return sw.SetRow("A1", header)
+}
+
+// cellStyle returns the number format for decimal and timestamp columns, or 0.
+func (x *xlsxRowWriter) cellStyle(c column) (int, error) {
+	var format string
+	switch c.Type {
+	case colDecimal:
+		format = "0." + strings.Repeat("0", int(c.Scale))
+	case colTimestamp:
+		format = "yyyy-mm-dd hh:mm:ss"
+		if f := c.Field.Format; f != "" && !hasTimeOfDay(f) {
+			format = "yyyy-mm-dd"
+		}
+	default:
+		return 0, nil
+	}
+	return x.book.NewStyle(&excelize.Style{CustomNumFmt: &format})
+}
+
+// hasTimeOfDay reports whether a Go time layout has hours, minutes or seconds.
+func hasTimeOfDay(layout string) bool {
+	for _, part := range []string{"15", "03", "04", "05"} {
+		if strings.Contains(layout, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func (x *xlsxRowWriter) WriteRow(_ []string, values []models.Value) error {
+	row := make([]any, len(values))
+	for i, v := range values {
+		cv, err := xlsxValue(x.columns[i], v)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", x.columns[i].Field.Name, err)
+		}
+		row[i] = excelize.Cell{StyleID: x.styles[i], Value: cv}
+	}
+
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.sw.SetRow(cell, row)
+}
+
+func (x *xlsxRowWriter) Flush() error {
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.book.Write(x.w)
+}
+
+// xlsxValue converts a value to the cell type for its column; null is an empty cell.
+func xlsxValue(c column, v models.Value) (any, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+
+	switch c.Type {
+	case colLong:
+		return longValue(v)
+	case colDouble:
+		return doubleValue(v)
+	case colDecimal:
+		d, err := decimalValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return d.InexactFloat64(), nil
+	case colTimestamp:
+		return timestampValue(v, c.Field)
+	case colBool:
+		return boolValue(v)
+	default:
+		return v.Text, nil
+	}
+}
@@ -0,0 +1,157 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/kream404/spoof/models"
+)
+
+const (
+	rowsToken       = "${rows}"
+	defaultEnvelope = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<rows>\n" + rowsToken + "</rows>\n"
+)
+
+var xmlPlaceholder = regexp.MustCompile(`\$\{([a-zA-Z0-9_.-]+)\}`)
+
+// xmlRowWriter renders each row through the row template. The rows are wrapped
+// in the envelope once the file is generated, see Document.
+type xmlRowWriter struct {
+	w        io.Writer
+	template string
+	before   string // envelope up to ${rows}
+	after    string
+	buf      bytes.Buffer
+}
+
+func newXMLRowWriter(file models.Entity, w io.Writer) (*xmlRowWriter, error) {
+	opts := file.Config.XML
+	if opts == nil || strings.TrimSpace(opts.Template) == "" {
+		return nil, fmt.Errorf("xml format requires xml.template")
+	}
+
+	tpl, err := os.ReadFile(opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("read xml template: %w", err)
+	}
+	envelope := defaultEnvelope
+	if opts.Envelope != "" {
+		raw, err := os.ReadFile(opts.Envelope)
+		if err != nil {
+			return nil, fmt.Errorf("read xml envelope: %w", err)
+		}
+		envelope = string(raw)
+	}
+
+	// the XML declaration must be the very first thing in the document
+	before, after, ok := strings.Cut(strings.TrimLeft(envelope, " \t\r\n"), rowsToken)
+	if !ok || strings.Contains(after, rowsToken) {
+		return nil, fmt.Errorf("xml envelope must contain %s exactly once", rowsToken)
+	}
+
+	if err := validateControlLine(before+after, file.Fields); err != nil {
+		return nil, fmt.Errorf("xml envelope: %w", err)
+	}
+
+	x := &xmlRowWriter{w: w, template: strings.TrimSpace(string(tpl)), before: before, after: after}
+	for _, m := range xmlPlaceholder.FindAllStringSubmatch(x.template, -1) {
+		if !hasField(file.Fields, m[1]) {
+			return nil, fmt.Errorf("xml template: no field named %q", m[1])
+		}
+	}
+
+	// a row rendered with empty values must already be well formed
+	if err := checkWellFormed(strings.NewReader("<row>" + xmlPlaceholder.ReplaceAllString(x.template, "") + "</row>")); err != nil {
+		return nil, fmt.Errorf("xml template: %w", err)
+	}
+	return x, nil
+}
+
+func (x *xmlRowWriter) WriteHeader([]string) error { return nil }
+
+// WriteRow renders the template with the row's values, escaped for XML. Null values are empty.
+func (x *xmlRowWriter) WriteRow(names []string, values []models.Value) error {
+	row := make(map[string]string, len(names))
+	for i, n := range names {
+		row[n] = values[i].Text
+	}
+
+	rendered := xmlPlaceholder.ReplaceAllStringFunc(x.template, func(token string) string {
+		x.buf.Reset()
+		_ = xml.EscapeText(&x.buf, []byte(row[token[2:len(token)-1]]))
+		return x.buf.String()
+	})
+	_, err := io.WriteString(x.w, rendered+"\n")
+	return err
+}
+
+func (x *xmlRowWriter) Flush() error { return nil }
+
+// Document writes the rendered rows to w wrapped in the envelope, with its
+// control tokens filled (see renderControlLine), once it has checked that the
+// result is well-formed XML.
+func (x *xmlRowWriter) Document(w io.Writer, rows *spool, control controlTotals) error {
+	before, err := renderControlLine(x.before, control)
+	if err != nil {
+		return fmt.Errorf("xml envelope: %w", err)
+	}
+	after, err := renderControlLine(x.after, control)
+	if err != nil {
+		return fmt.Errorf("xml envelope: %w", err)
+	}
+
+	body, err := rows.Reader()
+	if err != nil {
+		return err
+	}
+	if err := checkWellFormed(io.MultiReader(strings.NewReader(before), body, strings.NewReader(after))); err != nil {
+		return fmt.Errorf("generated xml is not well formed: %w", err)
+	}
+
+	if _, err := io.WriteString(w, before); err != nil {
+		return err
+	}
+	if _, err := rows.WriteTo(w); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, after)
+	return err
+}
+
+// checkWellFormed parses doc, which must have exactly one root element.
+func checkWellFormed(doc io.Reader) error {
+	d := xml.NewDecoder(doc)
+	depth, roots := 0, 0
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return fmt.Errorf("text outside the root element")
+			}
+		}
+	}
+	if roots != 1 {
+		return fmt.Errorf("expected one root element, found %d", roots)
+	}
+	return nil
+}
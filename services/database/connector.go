@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 	log "github.com/kream404/spoof/services/logger"
 
 	_ "github.com/lib/pq"
@@ -138,7 +139,13 @@ func (d *DBConnector) InsertRows(path string, config models.Entity) (int, error)
 	}
 	defer file.Close()
 
-	r := csv.NewReader(file)
+	body, err := compress.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("decompress csv: %w", err)
+	}
+	defer body.Close()
+
+	r := csv.NewReader(body)
 	if config.Config.Delimiter[0] != 0 {
 		r.Comma = rune(config.Config.Delimiter[0])
 	}
@@ -295,7 +302,13 @@ func (d *DBConnector) DeleteRowsByKeyFromFile(path string, config models.Entity)
 	}
 	defer file.Close()
 
-	r := csv.NewReader(file)
+	body, err := compress.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("decompress csv: %w", err)
+	}
+	defer body.Close()
+
+	r := csv.NewReader(body)
 	if config.Config.Delimiter != "" && config.Config.Delimiter[0] != 0 {
 		r.Comma = rune(config.Config.Delimiter[0])
 	}
@@ -428,9 +441,5 @@ func (d *DBConnector) DeleteRowsByKeyFromFile(path string, config models.Entity)
 }
 
 func quoteIdent(ident string) string {
-	ident = strings.TrimSpace(ident)
-	if ident == "" {
-		return ""
-	}
-	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	return QuoteIdent(Postgres, ident)
 }
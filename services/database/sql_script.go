@@ -0,0 +1,59 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is the SQL flavour used when writing .sql scripts.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+func ParseDialect(s string) (Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "postgres", "postgresql":
+		return Postgres, nil
+	case "mysql", "mariadb":
+		return MySQL, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return "", fmt.Errorf("unsupported sql dialect %q (expected postgres, mysql or sqlite)", s)
+	}
+}
+
+// QuoteIdent quotes an identifier for the dialect: "name" with "" escaping, or `name` for MySQL.
+func QuoteIdent(d Dialect, ident string) string {
+	ident = strings.TrimSpace(ident)
+	if ident == "" {
+		return ""
+	}
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
+
+// QualifiedTable returns schema.table, or just table when there is no schema.
+func QualifiedTable(d Dialect, schema, table string) string {
+	if strings.TrimSpace(schema) == "" {
+		return QuoteIdent(d, table)
+	}
+	return QuoteIdent(d, schema) + "." + QuoteIdent(d, table)
+}
+
+// QuoteLiteral quotes a string literal. MySQL also treats backslash as an escape character.
+func QuoteLiteral(d Dialect, s string) string {
+	if d == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// CopyEscape escapes a value for the Postgres COPY text format.
+var CopyEscape = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace
@@ -0,0 +1,21 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kream404/spoof/services/database"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"my ""table"""`, database.QuoteIdent(database.Postgres, `my "table"`))
+	assert.Equal(t, "`my``table`", database.QuoteIdent(database.MySQL, "my`table"))
+	assert.Equal(t, `"s"."t"`, database.QualifiedTable(database.SQLite, "s", "t"))
+	assert.Equal(t, `"t"`, database.QualifiedTable(database.Postgres, "", "t"))
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	assert.Equal(t, `'it''s \n'`, database.QuoteLiteral(database.Postgres, `it's \n`))
+	assert.Equal(t, `'it''s \\n'`, database.QuoteLiteral(database.MySQL, `it's \n`))
+}
@@ -3,6 +3,7 @@ package detector
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 
 	json_writer "github.com/kream404/spoof/services/json"
 
+	"github.com/kream404/spoof/fakers"
 	"github.com/kream404/spoof/models"
 	log "github.com/kream404/spoof/services/logger"
 )
@@ -351,9 +353,12 @@ func inferJSONLeaf(ctx *infCtx, v any, path []string) (any, []models.Field) {
 	key := makeKey(ctx, path)
 	mf := inferFieldFromValue(key, v)
 	phType := ""
-	if mf.Type == "number" {
+	switch mf.Type {
+	case "number":
 		phType = "number"
-	} else {
+	case "boolean":
+		phType = "bool"
+	default:
 		phType = "string"
 	}
 
@@ -446,25 +451,11 @@ func InferField(name string, col []string) (models.Field, error) {
 
 	// 7) Number
 	if ok, decimals, length := isNumber(sample); ok {
-		if length == 2 {
-			return models.Field{Name: name, Type: "number", Min: 1, Max: 99}, nil
-		}
-		if length > 0 {
+		// fixed-width integers are identifiers (account numbers etc.), not quantities
+		if length > 2 {
 			return models.Field{Name: name, Type: "number", Length: length}, nil
 		}
-		if decimals == 2 {
-			return models.Field{
-				Name:   name,
-				Type:   "number",
-				Format: fmt.Sprint(decimals),
-				Length: length,
-				Min:    1,
-				Max:    99,
-				Function: "sin:period=0.01,amplitude=1.5,center=50," +
-					"jitter=0.005,jitter_type=scale,jitter_amp=3",
-			}, nil
-		}
-		return models.Field{Name: name, Type: "number"}, nil
+		return inferNumericRange(name, col, decimals), nil
 	}
 
 	// 10) Small range (categorical)
@@ -500,6 +491,51 @@ func InferField(name string, col []string) (models.Field, error) {
 	return models.Field{Name: name, Type: "unknown"}, nil
 }
 
+// inferNumericRange fits an empirical distribution to the numeric values in col
+// so generated values follow the shape of the sample.
+func inferNumericRange(name string, col []string, decimals int) models.Field {
+	var values []float64
+	for _, v := range col {
+		ok, d, _ := isNumber(v)
+		if !ok {
+			continue
+		}
+		f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		values = append(values, f)
+		if d > decimals {
+			decimals = d
+		}
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	field := models.Field{
+		Name:   name,
+		Type:   "number",
+		Format: strconv.Itoa(decimals),
+	}
+
+	// a single distinct value has no shape to fit, so give it room either side
+	if lo == hi {
+		spread := math.Abs(lo)
+		if spread == 0 {
+			spread = 1
+		}
+		field.Min = models.Bound(strconv.FormatFloat(lo-spread, 'f', decimals, 64))
+		field.Max = models.Bound(strconv.FormatFloat(lo+spread, 'f', decimals, 64))
+		return field
+	}
+
+	field.Min = models.Bound(strconv.FormatFloat(lo, 'f', decimals, 64))
+	field.Max = models.Bound(strconv.FormatFloat(hi, 'f', decimals, 64))
+	field.Function = fakers.EmpiricalFunction(values, decimals)
+	return field
+}
+
 func inferFieldFromValue(name string, v any) models.Field {
 	switch x := v.(type) {
 	case string:
@@ -520,7 +556,7 @@ func inferFieldFromValue(name string, v any) models.Field {
 		return f
 
 	case bool:
-		return models.Field{Name: name, Type: "range", Values: "true, false"}
+		return models.Field{Name: name, Type: "boolean"}
 
 	case nil:
 		return models.Field{Name: name, Value: ""}
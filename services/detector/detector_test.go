@@ -0,0 +1,45 @@
+package detector_test
+
+import (
+	"testing"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/detector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferField_NumericEmpirical(t *testing.T) {
+	field, err := detector.InferField("amount", []string{"12.50", "3.10", "", "99.99", "45.00"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "number", field.Type)
+	assert.Equal(t, "2", field.Format)
+	assert.Equal(t, models.Bound("3.10"), field.Min)
+	assert.Equal(t, models.Bound("99.99"), field.Max)
+	assert.Equal(t, "empirical:quantiles=3.10|12.50|45.00|99.99", field.Function)
+}
+
+func TestInferField_Identifier(t *testing.T) {
+	field, err := detector.InferField("account", []string{"10042871", "10093312"})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Field{Name: "account", Type: "number", Length: 8}, field)
+}
+
+func TestInferFixedWidth(t *testing.T) {
+	lines := []string{
+		"000123JOHN SMITH     12.50GB",
+		"004567JANE DOE        3.10FR",
+		"000089AL JONES      199.99GB",
+	}
+
+	spans := detector.InferFixedWidth(lines)
+	assert.Equal(t, []detector.Span{{0, 6}, {6, 10}, {16, 10}, {26, 2}}, spans)
+	assert.Equal(t, []string{"004567", "JANE DOE", "3.10", "FR"}, detector.SplitFixed(lines[1], spans))
+
+	align, pad := detector.InferFixedLayout([]string{"000123", "004567", "000089"})
+	assert.Equal(t, "right", align)
+	assert.Equal(t, "0", pad)
+	align, pad = detector.InferFixedLayout([]string{"   12.50", "    3.10", "  199.99"})
+	assert.Equal(t, "right", align)
+	assert.Equal(t, "", pad)
+}
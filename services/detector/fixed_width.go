@@ -0,0 +1,151 @@
+package detector
+
+import (
+	"strings"
+	"unicode"
+)
+
+//
+// ───────────────────────── FIXED WIDTH ────────────────────────────
+//
+
+// Span is one column of a fixed-width record: runes [Start, Start+Width).
+type Span struct {
+	Start int
+	Width int
+}
+
+// InferFixedWidth infers column boundaries from fixed-width records. A column
+// starts after a run of positions that are blank in every line, or where every
+// line goes from a digit to a letter (a zero-padded number followed by text).
+// Each blank run is padding for the column before it, unless the column after
+// it is right aligned (its values start at different positions).
+func InferFixedWidth(lines []string) []Span {
+	rows := make([][]rune, 0, len(lines))
+	width := 0
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		r := []rune(l)
+		rows = append(rows, r)
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return nil
+	}
+
+	blank := make([]bool, width)
+	for i := range blank {
+		blank[i] = true
+		for _, r := range rows {
+			if i < len(r) && r[i] != ' ' {
+				blank[i] = false
+				break
+			}
+		}
+	}
+
+	type region struct{ start, end int }
+	var regions []region
+	for i := 0; i < width; {
+		if blank[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < width && !blank[i] {
+			if i > start && digitToLetter(rows, i) {
+				regions = append(regions, region{start, i})
+				start = i
+			}
+			i++
+		}
+		regions = append(regions, region{start, i})
+	}
+
+	spans := make([]Span, len(regions))
+	starts := make([]int, len(regions))
+	for k, rg := range regions {
+		starts[k] = rg.start
+	}
+	starts[0] = 0
+	for k := 1; k < len(regions); k++ {
+		gap := regions[k].start - regions[k-1].end
+		if gap > 0 && raggedLeft(rows, regions[k].start) {
+			starts[k] = regions[k-1].end
+		}
+	}
+	for k := range regions {
+		end := width
+		if k+1 < len(regions) {
+			end = starts[k+1]
+		}
+		spans[k] = Span{Start: starts[k], Width: end - starts[k]}
+	}
+	return spans
+}
+
+// SplitFixed cuts a record into its columns' values, trimmed of padding spaces.
+func SplitFixed(line string, spans []Span) []string {
+	r := []rune(line)
+	out := make([]string, len(spans))
+	for i, s := range spans {
+		start, end := min(s.Start, len(r)), min(s.Start+s.Width, len(r))
+		out[i] = strings.TrimSpace(string(r[start:end]))
+	}
+	return out
+}
+
+// InferFixedLayout infers a column's align and pad_char from its raw, untrimmed
+// values: right aligned when values are padded on the left, with a "0" pad when
+// numbers carry leading zeroes.
+func InferFixedLayout(raw []string) (align, pad string) {
+	leading, trailing, zeroes, numbers := 0, 0, 0, 0
+	for _, v := range raw {
+		t := strings.TrimSpace(v)
+		if t == "" {
+			continue
+		}
+		if strings.HasPrefix(v, " ") {
+			leading++
+		}
+		if strings.HasSuffix(v, " ") {
+			trailing++
+		}
+		if ok, _, _ := isNumber(t); ok {
+			numbers++
+			if digits := strings.TrimLeft(t, "-+"); len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+				zeroes++
+			}
+		}
+	}
+	switch {
+	case numbers > 0 && zeroes == numbers:
+		return "right", "0"
+	case leading > trailing:
+		return "right", ""
+	default:
+		return "", ""
+	}
+}
+
+// digitToLetter reports whether every line has a digit at i-1 and a letter at i.
+func digitToLetter(rows [][]rune, i int) bool {
+	for _, r := range rows {
+		if i >= len(r) || !unicode.IsDigit(r[i-1]) || !unicode.IsLetter(r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// raggedLeft reports whether some line's value in the column starting at start is padded on the left.
+func raggedLeft(rows [][]rune, start int) bool {
+	for _, r := range rows {
+		if start < len(r) && r[start] == ' ' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package evaluator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/kream404/spoof/models"
+)
+
+// Aggregate totals the detail records in a scope (one batch, or the whole file)
+// for fields of type aggregate in header and trailer records.
+type Aggregate struct {
+	Count   int
+	Batches int
+
+	sums   map[string]decimal.Decimal
+	mins   map[string]decimal.Decimal
+	maxs   map[string]decimal.Decimal
+	places map[string]int32
+}
+
+func NewAggregate() *Aggregate {
+	return &Aggregate{
+		sums:   make(map[string]decimal.Decimal),
+		mins:   make(map[string]decimal.Decimal),
+		maxs:   make(map[string]decimal.Decimal),
+		places: make(map[string]int32),
+	}
+}
+
+// Add counts a detail record, totalling its numeric values by field name.
+func (a *Aggregate) Add(generated map[string]string) {
+	a.Count++
+	for name, text := range generated {
+		d, err := decimal.NewFromString(strings.TrimSpace(text))
+		if err != nil {
+			continue
+		}
+		if _, ok := a.sums[name]; !ok {
+			a.mins[name], a.maxs[name] = d, d
+		}
+		a.sums[name] = a.sums[name].Add(d)
+		if d.LessThan(a.mins[name]) {
+			a.mins[name] = d
+		}
+		if d.GreaterThan(a.maxs[name]) {
+			a.maxs[name] = d
+		}
+		if p := -d.Exponent(); p > a.places[name] {
+			a.places[name] = p
+		}
+	}
+}
+
+// Value resolves an aggregate field. function is count, batches, sum, min, max or
+// avg; all but count and batches total the target field. Totals keep the target's
+// decimal places (at least 2 for avg) unless format sets them.
+func (a *Aggregate) Value(field models.Field) (models.Value, error) {
+	fn := strings.ToLower(strings.TrimSpace(field.Function))
+	switch fn {
+	case "count":
+		return models.IntValue(int64(a.Count)), nil
+	case "batches":
+		return models.IntValue(int64(a.Batches)), nil
+	case "sum", "min", "max", "avg":
+	default:
+		return models.Value{}, fmt.Errorf("unsupported aggregate function %q (expected count, batches, sum, min, max or avg)", field.Function)
+	}
+
+	if field.Target == "" {
+		return models.Value{}, fmt.Errorf("aggregate %s requires a 'target' detail field", fn)
+	}
+	places := a.places[field.Target]
+	if field.Format != "" {
+		p, err := strconv.Atoi(field.Format)
+		if err != nil || p < 0 {
+			return models.Value{}, fmt.Errorf("invalid aggregate format %q (expected decimal places)", field.Format)
+		}
+		places = int32(p)
+	}
+
+	sum, ok := a.sums[field.Target]
+	if !ok && fn != "sum" {
+		return models.NullValue(), nil
+	}
+
+	var d decimal.Decimal
+	switch fn {
+	case "sum":
+		d = sum
+	case "min":
+		d = a.mins[field.Target]
+	case "max":
+		d = a.maxs[field.Target]
+	case "avg":
+		d = sum.Div(decimal.NewFromInt(int64(a.Count)))
+		if field.Format == "" {
+			places = max(places, 2)
+		}
+	}
+	if places == 0 {
+		return models.IntValue(d.Round(0).IntPart()), nil
+	}
+	return models.DecimalValue(d, places), nil
+}
@@ -8,9 +8,11 @@ import (
 	jsonstd "encoding/json" // if you already alias this elsewhere, keep consistent
 
 	"github.com/kream404/spoof/fakers"
+	"github.com/kream404/spoof/interfaces"
 	"github.com/kream404/spoof/models"
 	"github.com/kream404/spoof/services/json" // keep your existing json pkg alias as needed
 	"github.com/kream404/spoof/services/logger"
+	"github.com/kream404/spoof/services/script"
 	"github.com/shopspring/decimal"
 )
 
@@ -27,12 +29,32 @@ type evalCtx struct {
 	// generated scopes (keyed by output key: alias if present else name)
 	generated       map[string]string
 	parentGenerated map[string]string
+	values          map[string]models.Value // typed twin of generated, so reflection keeps the type
+	types           map[string]string       // field type by output key, for reflection
 
 	// selector (optional)
 	seedSelector *models.SeedSelector
 
 	// injection gating
 	shouldInject func(models.Field, *rand.Rand) bool
+
+	// detail totals for aggregate fields in header/trailer records (optional)
+	aggregate *Aggregate
+
+	// the file's reused fakers (optional), and the key prefix of nested fields
+	fakers *Fakers
+	scope  string
+}
+
+// Fakers holds a file's Stateful fakers, so each is created once per field and
+// reused for every row. Rows is the file's row count.
+type Fakers struct {
+	Rows   int
+	fakers map[string]interfaces.Faker[any]
+}
+
+func NewFakers(rows int) *Fakers {
+	return &Fakers{Rows: rows, fakers: make(map[string]interfaces.Faker[any])}
 }
 
 func lookupKey(field models.Field) string {
@@ -178,64 +200,74 @@ func (c *evalCtx) trySeed(field models.Field, key string) (any, bool, error) {
 	return nil, false, nil
 }
 
-func (c *evalCtx) resolveReflection(field models.Field) (string, error) {
+// lookup returns a value already generated in this row, falling back to the parent scope.
+func (c *evalCtx) lookup(name string) (string, bool) {
+	if v, ok := c.generated[name]; ok {
+		return v, true
+	}
+	if c.parentGenerated != nil {
+		if v, ok := c.parentGenerated[name]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (c *evalCtx) value(name string) (models.Value, bool) {
+	v, ok := c.values[name]
+	return v, ok
+}
+
+func (c *evalCtx) resolveReflection(field models.Field) (models.Value, error) {
 	if field.Target == "" {
-		return "", fmt.Errorf("you must provide a 'target' to use reflection")
+		return models.Value{}, fmt.Errorf("you must provide a 'target' to use reflection")
 	}
 
-	if v, ok := c.generated[field.Target]; ok {
+	if v, ok := c.values[field.Target]; ok {
 		return v, nil
 	}
-	if c.parentGenerated != nil {
-		if v, ok := c.parentGenerated[field.Target]; ok {
-			return v, nil
-		}
+	if v, ok := c.lookup(field.Target); ok {
+		return models.StringValue(v), nil
 	}
 
 	for k, v := range c.generated {
 		if k == field.Target {
-			return v, nil
+			return models.StringValue(v), nil
 		}
 		_ = k
 	}
 	if c.parentGenerated != nil {
 		for k, v := range c.parentGenerated {
 			if k == field.Target {
-				return v, nil
+				return models.StringValue(v), nil
 			}
 			_ = k
 		}
 	}
 
-	return "", fmt.Errorf("reflection target '%s' not found in previous fields", field.Target)
+	return models.Value{}, fmt.Errorf("reflection target '%s' not found in previous fields", field.Target)
 }
 
-func (c *evalCtx) evaluateField(field models.Field) (string, error) {
+// evaluateField generates one cell. The value keeps its type (see models.Value);
+// formatting to text happens in the writers.
+func (c *evalCtx) evaluateField(field models.Field) (models.Value, error) {
 	lk := lookupKey(field) // for cache/source lookup
-	okey := outKey(field)  // for generated/output maps
+
+	// 0) null
+	if c.isNull(field) {
+		return c.store(field, models.NullValue())
+	}
 
 	// 1) injection
 	if val, ok := c.tryInjectFromSource(field, lk); ok {
-		s := fmt.Sprint(val)
-		out, err := applyModifier(s, field)
-		if err != nil {
-			return "", fmt.Errorf("modifier failed for field %s: %w", field.Name, err)
-		}
-		c.generated[okey] = out
-		return out, nil
+		return c.store(field, models.ValueOf(val))
 	}
 
 	// 2) seed
 	if val, ok, err := c.trySeed(field, lk); err != nil {
-		return "", err
+		return models.Value{}, err
 	} else if ok {
-		s := fmt.Sprint(val)
-		out, err := applyModifier(s, field)
-		if err != nil {
-			return "", fmt.Errorf("modifier failed for field %s: %w", field.Name, err)
-		}
-		c.generated[okey] = out
-		return out, nil
+		return c.store(field, models.ValueOf(val))
 	}
 
 	// 3) compute fallback
@@ -245,16 +277,28 @@ func (c *evalCtx) evaluateField(field models.Field) (string, error) {
 	case field.Type == "reflection":
 		targetValue, err := c.resolveReflection(field)
 		if err != nil {
-			return "", err
+			return models.Value{}, err
 		}
 		value = targetValue
-		if field.Modifier != "" {
-			modified, err := modifier(targetValue, field.Modifier)
+		// format derives a card representation (masked/expiry/cvv) from a reflected PAN;
+		// the modifier is applied to the result by store
+		if field.Format != "" && c.types[field.Target] == "card" && !targetValue.IsNull() {
+			derived, err := fakers.CardVariant(targetValue.Text, field.Format)
 			if err != nil {
-				return "", err
+				return models.Value{}, fmt.Errorf("reflection failed for field %s: %w", field.Name, err)
 			}
-			value = modified
+			value = derived
+		}
+
+	case field.Type == "aggregate":
+		if c.aggregate == nil {
+			return models.Value{}, fmt.Errorf("field %s: aggregate fields are only supported in header and trailer records", field.Name)
+		}
+		v, err := c.aggregate.Value(field)
+		if err != nil {
+			return models.Value{}, fmt.Errorf("field %s: %w", field.Name, err)
 		}
+		value = v
 
 	case field.Type == "iterator":
 		start := 1
@@ -278,36 +322,38 @@ func (c *evalCtx) evaluateField(field models.Field) (string, error) {
 		}
 		value = cleaned[idx]
 
+	case field.Type == "script":
+		v, err := script.Eval(field, script.Env{
+			Index:  c.rowIndex,
+			Row:    c.generated,
+			Parent: c.parentGenerated,
+			Rng:    c.rng,
+		})
+		if err != nil {
+			return models.Value{}, fmt.Errorf("script failed for field %s: %w", field.Name, err)
+		}
+		value = v
+
 	case field.Type == "json":
 		cj, err := json.CompileJSONField(field, field.Template)
 		if err != nil {
-			return "", err
+			return models.Value{}, err
 		}
 
 		raw := strings.TrimSpace(cj.Raw)
 		rootIsArray := strings.HasPrefix(raw, "[")
 
 		if !rootIsArray {
-			kv, err := GenerateNestedValuesUnified(
-				c.rowIndex,
-				c.seedIndex,
-				c.rng,
-				cj.Fields,
-				c.cache,
-				c.fieldSources,
-				c.generated, // parent for nested
-				c.shouldInject,
-				c.seedSelector,
-			)
+			kv, err := c.nested(field, c.seedIndex).evaluateNested(cj.Fields)
 			if err != nil {
-				return "", err
+				return models.Value{}, err
 			}
 
-			s, err := json.RenderJSONCell(cj.Raw, kv)
+			s, err := json.RenderJSONValues(cj.Raw, kv)
 			if err != nil {
-				return "", err
+				return models.Value{}, err
 			}
-			value = s
+			value = models.JSONValue(s)
 			break
 		}
 
@@ -321,29 +367,19 @@ func (c *evalCtx) evaluateField(field models.Field) (string, error) {
 		for j := 0; j < repeat; j++ {
 			iterSeed := c.seedIndex + j
 
-			kv, err := GenerateNestedValuesUnified(
-				c.rowIndex,
-				iterSeed,
-				c.rng,
-				cj.Fields,
-				c.cache,
-				c.fieldSources,
-				c.generated, // parent for nested
-				c.shouldInject,
-				c.seedSelector,
-			)
+			kv, err := c.nested(field, iterSeed).evaluateNested(cj.Fields)
 			if err != nil {
-				return "", err
+				return models.Value{}, err
 			}
 
-			rendered, err := json.RenderJSONCell(cj.Raw, kv)
+			rendered, err := json.RenderJSONValues(cj.Raw, kv)
 			if err != nil {
-				return "", err
+				return models.Value{}, err
 			}
 
 			var arr []any
 			if err := jsonstd.Unmarshal([]byte(rendered), &arr); err != nil {
-				return "", fmt.Errorf(
+				return models.Value{}, fmt.Errorf(
 					"invalid rendered JSON for %s (expected array root): %w\nrendered: %s",
 					field.Name, err, rendered,
 				)
@@ -356,48 +392,128 @@ func (c *evalCtx) evaluateField(field models.Field) (string, error) {
 
 		out, err := jsonstd.Marshal(items)
 		if err != nil {
-			return "", err
+			return models.Value{}, err
 		}
-		value = string(out)
+		value = models.JSONValue(string(out))
 
 	default:
-		factory, found := fakers.GetFakerByName(field.Type)
-		if !found {
-			return "", fmt.Errorf("faker not found for type: %s", field.Type)
-		}
-		faker, err := factory(field, c.rng)
+		faker, err := c.faker(field)
 		if err != nil {
-			return "", fmt.Errorf("error creating faker for field %s: %w", field.Name, err)
+			return models.Value{}, err
+		}
+		if ra, ok := faker.(interfaces.RowAware); ok {
+			row := models.Row{Index: c.rowIndex, Lookup: c.lookup, Value: c.value}
+			if c.fakers != nil {
+				row.Count = c.fakers.Rows
+			}
+			ra.SetRow(row)
 		}
 		v, err := faker.Generate()
 		if err != nil {
-			return "", fmt.Errorf("error generating value for field %s: %w", field.Name, err)
+			return models.Value{}, fmt.Errorf("error generating value for field %s: %w", field.Name, err)
 		}
 		value = v
 	}
 
-	s := ""
-	if value != nil {
-		s = fmt.Sprint(value)
+	return c.store(field, models.ValueOf(value))
+}
+
+// faker creates the field's faker, or returns the Stateful one made for an earlier row.
+func (c *evalCtx) faker(field models.Field) (interfaces.Faker[any], error) {
+	key := c.scope + outKey(field)
+	if c.fakers != nil {
+		if f, ok := c.fakers.fakers[key]; ok {
+			return f, nil
+		}
+	}
+
+	factory, found := fakers.GetFakerByName(field.Type)
+	if !found {
+		return nil, fmt.Errorf("faker not found for type: %s", field.Type)
+	}
+	faker, err := factory(field, c.rng)
+	if err != nil {
+		return nil, fmt.Errorf("error creating faker for field %s: %w", field.Name, err)
+	}
+	if _, ok := faker.(interfaces.Stateful); ok && c.fakers != nil {
+		c.fakers.fakers[key] = faker
+	}
+	return faker, nil
+}
+
+// nested is the context for the fields of a json field's template, which read
+// the row's fields as their parent.
+func (c *evalCtx) nested(field models.Field, seedIndex int) *evalCtx {
+	return &evalCtx{
+		rowIndex:        c.rowIndex,
+		seedIndex:       seedIndex,
+		rng:             c.rng,
+		cache:           c.cache,
+		fieldSources:    c.fieldSources,
+		parentGenerated: c.generated,
+		shouldInject:    c.shouldInject,
+		seedSelector:    c.seedSelector,
+		fakers:          c.fakers,
+		scope:           c.scope + outKey(field) + ".",
+	}
+}
+
+func (c *evalCtx) evaluateNested(fields []models.Field) (map[string]models.Value, error) {
+	values := make(map[string]models.Value, len(fields))
+	c.generated = make(map[string]string, len(fields))
+	c.values = make(map[string]models.Value, len(fields))
+	c.types = make(map[string]string, len(fields))
+
+	for _, field := range fields {
+		val, err := c.evaluateField(field)
+		if err != nil {
+			return nil, err
+		}
+
+		values[outKey(field)] = val
 	}
 
-	out, err := applyModifier(s, field)
+	return values, nil
+}
+
+// isNull draws whether the field is null in this row, for fields with a null_rate.
+func (c *evalCtx) isNull(field models.Field) bool {
+	if field.NullRate == nil || *field.NullRate <= 0 {
+		return false
+	}
+	if *field.NullRate >= 100 {
+		return true
+	}
+	return c.rng.Intn(100) < *field.NullRate
+}
+
+// store applies the field's modifier and records the value for later fields in the row.
+func (c *evalCtx) store(field models.Field, value models.Value) (models.Value, error) {
+	out, err := applyModifier(value, field)
 	if err != nil {
-		return "", fmt.Errorf("modifier failed for field %s: %w", field.Name, err)
+		return models.Value{}, fmt.Errorf("modifier failed for field %s: %w", field.Name, err)
 	}
 
-	c.generated[okey] = out
+	okey := outKey(field)
+	c.generated[okey] = out.Text
+	if c.types != nil {
+		c.types[okey] = field.Type
+	}
+	if c.values != nil {
+		c.values[okey] = out
+	}
 	return out, nil
 }
 
-func modifier(raw string, modifier string) (string, error) {
+// modify multiplies raw by modifier, keeping raw's decimal places.
+func modify(raw string, modifier string) (models.Value, error) {
 	decimalValue, err := decimal.NewFromString(raw)
 	if err != nil {
-		return "", fmt.Errorf("invalid number: %v", err)
+		return models.Value{}, fmt.Errorf("invalid number: %v", err)
 	}
 	modifierValue, err := decimal.NewFromString(modifier)
 	if err != nil {
-		return "", fmt.Errorf("invalid number: %v", err)
+		return models.Value{}, fmt.Errorf("invalid number: %v", err)
 	}
 
 	modifiedValue := decimalValue.Mul(modifierValue)
@@ -407,14 +523,14 @@ func modifier(raw string, modifier string) (string, error) {
 		decimals = len(raw) - dot - 1
 	}
 
-	return modifiedValue.StringFixed(int32(decimals)), nil
+	return models.DecimalValue(modifiedValue, int32(decimals)), nil
 }
 
-func applyModifier(val string, field models.Field) (string, error) {
-	if field.Modifier == "" {
+func applyModifier(val models.Value, field models.Field) (models.Value, error) {
+	if field.Modifier == "" || val.IsNull() {
 		return val, nil
 	}
-	return modifier(val, field.Modifier)
+	return modify(val.Text, field.Modifier)
 }
 
 func shouldInjectFromSource(field models.Field, rng *rand.Rand) bool {
@@ -469,31 +585,26 @@ func GenerateNestedValuesUnified(
 	seedSelector *models.SeedSelector,
 ) (map[string]string, error) {
 
-	values := make(map[string]string, len(fields))
-	localGenerated := make(map[string]string, len(fields))
-
-	ctx := evalCtx{
+	ctx := &evalCtx{
 		rowIndex:        rowIndex,
 		seedIndex:       seedIndex,
 		rng:             rng,
 		cache:           cache,
 		fieldSources:    fieldSources,
-		generated:       localGenerated,
 		parentGenerated: parentGenerated,
 		shouldInject:    shouldInject,
 		seedSelector:    seedSelector,
 	}
-
-	for _, field := range fields {
-		val, err := ctx.evaluateField(field)
-		if err != nil {
-			return nil, err
-		}
-
-		values[outKey(field)] = val
+	values, err := ctx.evaluateNested(fields)
+	if err != nil {
+		return nil, err
 	}
 
-	return values, nil
+	text := make(map[string]string, len(values))
+	for k, v := range values {
+		text[k] = v.Text
+	}
+	return text, nil
 }
 
 func GenerateValues(
@@ -503,10 +614,28 @@ func GenerateValues(
 	rowIndex int,
 	seedIndex int,
 	rng *rand.Rand,
-) ([]string, map[string]string, error) {
+	fakers *Fakers,
+) ([]models.Value, map[string]string, error) {
+	return GenerateRecord(file, file.Fields, nil, cache, fieldSources, rowIndex, seedIndex, rng, fakers)
+}
 
-	record := make([]string, 0, len(file.Fields))
-	generatedFields := make(map[string]string, len(file.Fields))
+// GenerateRecord generates one row of fields, which are the entity's own or one
+// of its header/trailer record types. Aggregate fields read agg. fakers may be
+// nil, and then every faker is created for the one row.
+func GenerateRecord(
+	file models.Entity,
+	fields []models.Field,
+	agg *Aggregate,
+	cache []map[string]any,
+	fieldSources map[string][]map[string]any,
+	rowIndex int,
+	seedIndex int,
+	rng *rand.Rand,
+	fakers *Fakers,
+) ([]models.Value, map[string]string, error) {
+
+	record := make([]models.Value, 0, len(fields))
+	generatedFields := make(map[string]string, len(fields))
 
 	ctx := evalCtx{
 		rowIndex:        rowIndex,
@@ -516,14 +645,18 @@ func GenerateValues(
 		fieldSources:    fieldSources,
 		generated:       generatedFields,
 		parentGenerated: nil,
+		values:          make(map[string]models.Value, len(fields)),
+		types:           make(map[string]string, len(fields)),
 		shouldInject:    shouldInjectFromSource,
+		aggregate:       agg,
+		fakers:          fakers,
 	}
 
 	if file.CacheConfig != nil {
 		ctx.seedSelector = file.CacheConfig.SeedSelector
 	}
 
-	for _, field := range file.Fields {
+	for _, field := range fields {
 		val, err := ctx.evaluateField(field)
 		if err != nil {
 			return nil, nil, err
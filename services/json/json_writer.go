@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/kream404/spoof/models"
 )
 
 func WriteTemplate(path string, contents string, overwrite bool) (string, error) {
@@ -39,7 +41,18 @@ func WriteTemplate(path string, contents string, overwrite bool) (string, error)
 
 var placeholderRe = regexp.MustCompile(`"\$\{([a-zA-Z0-9_]+)(?::([a-zA-Z0-9_]+))?\}"`)
 
+// RenderJSONCell renders the template from text values; see RenderJSONValues.
 func RenderJSONCell(tpl string, kv map[string]string) (string, error) {
+	values := make(map[string]models.Value, len(kv))
+	for k, v := range kv {
+		values[k] = models.StringValue(v)
+	}
+	return RenderJSONValues(tpl, values)
+}
+
+// RenderJSONValues replaces the "${key}", "${key:number}" and "${key:bool}"
+// placeholders in tpl with the values in kv. Missing and null values are null.
+func RenderJSONValues(tpl string, kv map[string]models.Value) (string, error) {
 	rendered := placeholderRe.ReplaceAllFunc([]byte(tpl), func(match []byte) []byte {
 		sub := placeholderRe.FindSubmatch(match)
 		if len(sub) < 2 {
@@ -54,7 +67,7 @@ func RenderJSONCell(tpl string, kv map[string]string) (string, error) {
 		}
 
 		val, ok := kv[key]
-		if !ok {
+		if !ok || val.IsNull() {
 			return []byte(`null`)
 		}
 
@@ -73,8 +86,9 @@ func RenderJSONCell(tpl string, kv map[string]string) (string, error) {
 	return string(compact), nil
 }
 
-func renderJSONLiteral(raw, typ string) string {
+func renderJSONLiteral(v models.Value, typ string) string {
 	t := strings.ToLower(strings.TrimSpace(typ))
+	raw := v.Text
 
 	if t == "number" {
 		s := strings.TrimSpace(raw)
@@ -88,6 +102,23 @@ func renderJSONLiteral(raw, typ string) string {
 		return s
 	}
 
+	if t == "bool" || t == "boolean" {
+		// a boolean field's value, whatever tokens its format writes
+		if v.Kind == models.KindBool {
+			return strconv.FormatBool(v.Bool)
+		}
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "true", "t", "y", "yes", "1":
+			return "true"
+		case "false", "f", "n", "no", "0":
+			return "false"
+		case "":
+			return "null"
+		}
+		b, _ := json.Marshal(raw)
+		return string(b)
+	}
+
 	// default: treat as string
 	b, _ := json.Marshal(raw)
 	return string(b)
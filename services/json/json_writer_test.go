@@ -0,0 +1,64 @@
+package json_test
+
+import (
+	"testing"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderJSONCell_PlaceholderTypes(t *testing.T) {
+	tpl := `{"id": "${id}", "amount": "${amount:number}", "active": "${active:bool}", "flag": "${flag:bool}"}`
+
+	tests := []struct {
+		name string
+		kv   map[string]string
+		want string
+	}{
+		{
+			name: "typed values",
+			kv:   map[string]string{"id": "7", "amount": "12.50", "active": "true", "flag": "N"},
+			want: `{"active":true,"amount":12.5,"flag":false,"id":"7"}`,
+		},
+		{
+			name: "numeric tokens",
+			kv:   map[string]string{"id": "7", "amount": "1", "active": "1", "flag": "0"},
+			want: `{"active":true,"amount":1,"flag":false,"id":"7"}`,
+		},
+		{
+			name: "unrecognised bool falls back to string",
+			kv:   map[string]string{"id": "7", "amount": "1", "active": "maybe", "flag": "T"},
+			want: `{"active":"maybe","amount":1,"flag":true,"id":"7"}`,
+		},
+		{
+			name: "empty bool renders null",
+			kv:   map[string]string{"id": "7", "amount": "1", "active": "", "flag": "N"},
+			want: `{"active":null,"amount":1,"flag":false,"id":"7"}`,
+		},
+		{
+			name: "missing keys render null",
+			kv:   map[string]string{"id": "7"},
+			want: `{"active":null,"amount":null,"flag":null,"id":"7"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.RenderJSONCell(tpl, tt.kv)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderJSONValues_TypedBool(t *testing.T) {
+	tpl := `{"status": "${status:bool}", "closed": "${closed:bool}", "note": "${note}"}`
+	got, err := json.RenderJSONValues(tpl, map[string]models.Value{
+		"status": models.BoolText(true, "Active"),
+		"closed": models.NullValue(),
+		"note":   models.NullValue(),
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status":true,"closed":null,"note":null}`, got)
+}
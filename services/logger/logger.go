@@ -34,8 +34,9 @@ func Error(msg string, args ...any) {
 	}
 }
 
+// Init logs to stderr, so stdout only carries generated data.
 func Init(level slog.Level) {
-	w := os.Stdout
+	w := os.Stderr
 
 	handler := tint.NewHandler(w, &tint.Options{
 		TimeFormat: time.TimeOnly,
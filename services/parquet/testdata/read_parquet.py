@@ -0,0 +1,27 @@
+"""Reads a parquet file with pyarrow and prints its schema and rows as JSON, so
+the writer's output is checked by a reader that shares none of its code."""
+
+import json
+import sys
+import uuid
+
+import pyarrow.parquet as pq
+
+
+def plain(v):
+    if v is None or isinstance(v, (bool, int, float, str)):
+        return v
+    if isinstance(v, bytes):
+        return v.hex()
+    if isinstance(v, uuid.UUID):  # newer pyarrow reads the uuid logical type as UUID
+        return v.hex
+    if hasattr(v, "isoformat"):
+        return v.isoformat()
+    return str(v)  # Decimal
+
+
+table = pq.read_table(sys.argv[1])
+print(json.dumps({
+    "schema": {f.name: str(f.type) for f in table.schema},
+    "rows": [{k: plain(v) for k, v in row.items()} for row in table.to_pylist()],
+}))
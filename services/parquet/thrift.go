@@ -0,0 +1,96 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types.
+const (
+	tBoolTrue  = 1
+	tBoolFalse = 2
+	tI32       = 5
+	tI64       = 6
+	tBinary    = 8
+	tList      = 9
+	tStruct    = 12
+)
+
+// encoder writes the thrift compact protocol, which parquet uses for page
+// headers and the footer. Only what those structs need is implemented.
+type encoder struct {
+	buf  bytes.Buffer
+	last []int16 // last field id of each open struct
+}
+
+func (e *encoder) Bytes() []byte { return e.buf.Bytes() }
+
+func (e *encoder) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	e.buf.Write(b[:n])
+}
+
+func (e *encoder) varint(v int64) { e.uvarint(uint64((v << 1) ^ (v >> 63))) }
+
+func (e *encoder) field(id int16, typ byte) {
+	last := &e.last[len(e.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		e.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		e.buf.WriteByte(typ)
+		e.varint(int64(id))
+	}
+	*last = id
+}
+
+func (e *encoder) begin()      { e.last = append(e.last, 0) }
+func (e *encoder) end()        { e.buf.WriteByte(0); e.last = e.last[:len(e.last)-1] }
+func (e *encoder) i32(v int32) { e.varint(int64(v)) }
+
+func (e *encoder) fieldI32(id int16, v int32) { e.field(id, tI32); e.varint(int64(v)) }
+func (e *encoder) fieldI64(id int16, v int64) { e.field(id, tI64); e.varint(v) }
+
+func (e *encoder) fieldString(id int16, s string) {
+	e.field(id, tBinary)
+	e.uvarint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
+
+func (e *encoder) fieldBool(id int16, v bool) {
+	if v {
+		e.field(id, tBoolTrue)
+	} else {
+		e.field(id, tBoolFalse)
+	}
+}
+
+// fieldStruct writes a nested struct; body writes its fields.
+func (e *encoder) fieldStruct(id int16, body func()) {
+	e.field(id, tStruct)
+	e.begin()
+	body()
+	e.end()
+}
+
+func (e *encoder) fieldList(id int16, elem byte, n int) {
+	e.field(id, tList)
+	if n < 15 {
+		e.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		e.buf.WriteByte(0xf0 | elem)
+		e.uvarint(uint64(n))
+	}
+}
+
+// listStruct writes one struct element of a list.
+func (e *encoder) listStruct(body func()) {
+	e.begin()
+	body()
+	e.end()
+}
+
+func (e *encoder) listString(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf.WriteString(s)
+}
@@ -0,0 +1,423 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Type is a parquet physical type.
+type Type int32
+
+const (
+	Boolean           Type = 0
+	Int64             Type = 2
+	Double            Type = 5
+	ByteArray         Type = 6
+	FixedLenByteArray Type = 7
+)
+
+// Logical is how readers should interpret a column's physical values.
+type Logical int
+
+const (
+	None      Logical = iota
+	String            // ByteArray, UTF-8
+	Decimal           // Int64 unscaled value with Scale and Precision
+	Timestamp         // Int64 microseconds since the epoch, UTC
+	JSON              // ByteArray
+	UUID              // FixedLenByteArray of 16
+)
+
+const (
+	DefaultRowGroupSize = 100_000
+	DefaultCompression  = "snappy"
+
+	magic     = "PAR1"
+	createdBy = "spoof"
+)
+
+// Column is one optional (nullable) column in the file schema.
+type Column struct {
+	Name      string
+	Type      Type
+	Logical   Logical
+	Scale     int32
+	Precision int32
+	Length    int32 // FixedLenByteArray
+}
+
+type Options struct {
+	RowGroupSize int    // rows per row group
+	Compression  string // none | snappy | gzip | zstd
+}
+
+// compression codecs, as in the parquet thrift spec
+var codecs = map[string]int32{"none": 0, "uncompressed": 0, "snappy": 1, "gzip": 2, "zstd": 6}
+
+// Writer writes rows to a parquet file. Values are given per column as nil,
+// bool, int64, float64, string or []byte, matching the column's physical type.
+type Writer struct {
+	w       *countingWriter
+	columns []Column
+	opts    Options
+	codec   int32
+	zstd    *zstd.Encoder
+
+	rows      [][]any
+	rowGroups []rowGroup
+	numRows   int64
+	closed    bool
+}
+
+type rowGroup struct {
+	numRows   int64
+	totalSize int64
+	chunks    []columnChunk
+}
+
+type columnChunk struct {
+	offset       int64
+	numValues    int64
+	uncompressed int64
+	compressed   int64
+}
+
+func NewWriter(w io.Writer, columns []Column, opts Options) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: no columns")
+	}
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = DefaultRowGroupSize
+	}
+	if opts.Compression == "" {
+		opts.Compression = DefaultCompression
+	}
+	codec, ok := codecs[strings.ToLower(opts.Compression)]
+	if !ok {
+		return nil, fmt.Errorf("parquet: unsupported compression %q (expected none, snappy, gzip or zstd)", opts.Compression)
+	}
+	for _, c := range columns {
+		if c.Type == FixedLenByteArray && c.Length <= 0 {
+			return nil, fmt.Errorf("parquet: column %s: fixed length must be set", c.Name)
+		}
+	}
+
+	pw := &Writer{w: &countingWriter{w: w}, columns: columns, opts: opts, codec: codec}
+	if codec == codecs["zstd"] {
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		pw.zstd = enc
+	}
+	if _, err := pw.w.Write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+func (w *Writer) Write(row []any) error {
+	if w.closed {
+		return fmt.Errorf("parquet: write after close")
+	}
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, schema has %d columns", len(row), len(w.columns))
+	}
+	for i, v := range row {
+		if err := check(w.columns[i], v); err != nil {
+			return err
+		}
+	}
+	w.rows = append(w.rows, row)
+	if len(w.rows) >= w.opts.RowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close writes the last row group and the footer. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if w.zstd != nil {
+		_ = w.zstd.Close()
+	}
+
+	footer := w.fileMetaData()
+	if _, err := w.w.Write(footer); err != nil {
+		return err
+	}
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(footer)))
+	if _, err := w.w.Write(n[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write([]byte(magic))
+	return err
+}
+
+func check(c Column, v any) error {
+	if v == nil {
+		return nil
+	}
+	ok := false
+	switch c.Type {
+	case Boolean:
+		_, ok = v.(bool)
+	case Int64:
+		_, ok = v.(int64)
+	case Double:
+		_, ok = v.(float64)
+	case ByteArray:
+		switch v.(type) {
+		case string, []byte:
+			ok = true
+		}
+	case FixedLenByteArray:
+		b, isBytes := v.([]byte)
+		ok = isBytes && len(b) == int(c.Length)
+	}
+	if !ok {
+		return fmt.Errorf("parquet: column %s: unexpected value %T", c.Name, v)
+	}
+	return nil
+}
+
+// flush writes the buffered rows as a row group with one data page per column.
+func (w *Writer) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	rg := rowGroup{numRows: int64(len(w.rows))}
+	for i, c := range w.columns {
+		chunk, err := w.writeColumn(i, c)
+		if err != nil {
+			return fmt.Errorf("parquet: column %s: %w", c.Name, err)
+		}
+		rg.chunks = append(rg.chunks, chunk)
+		rg.totalSize += chunk.uncompressed
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += rg.numRows
+	w.rows = w.rows[:0]
+	return nil
+}
+
+func (w *Writer) writeColumn(i int, c Column) (columnChunk, error) {
+	var page bytes.Buffer
+
+	// definition levels: 1 for a value, 0 for null, as bit-packed runs of width 1
+	levels := make([]byte, (len(w.rows)+7)/8)
+	for r, row := range w.rows {
+		if row[i] != nil {
+			levels[r/8] |= 1 << (r % 8)
+		}
+	}
+	var run bytes.Buffer
+	var hdr [binary.MaxVarintLen64]byte
+	run.Write(hdr[:binary.PutUvarint(hdr[:], uint64(len(levels))<<1|1)])
+	run.Write(levels)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(run.Len()))
+	page.Write(size[:])
+	page.Write(run.Bytes())
+
+	// PLAIN encoded values, nulls omitted
+	var bits []byte
+	nbits := 0
+	for _, row := range w.rows {
+		switch v := row[i].(type) {
+		case nil:
+		case bool:
+			if nbits%8 == 0 {
+				bits = append(bits, 0)
+			}
+			if v {
+				bits[nbits/8] |= 1 << (nbits % 8)
+			}
+			nbits++
+		case int64:
+			_ = binary.Write(&page, binary.LittleEndian, v)
+		case float64:
+			_ = binary.Write(&page, binary.LittleEndian, math.Float64bits(v))
+		case string:
+			if c.Type == ByteArray {
+				binary.LittleEndian.PutUint32(size[:], uint32(len(v)))
+				page.Write(size[:])
+			}
+			page.WriteString(v)
+		case []byte:
+			if c.Type == ByteArray {
+				binary.LittleEndian.PutUint32(size[:], uint32(len(v)))
+				page.Write(size[:])
+			}
+			page.Write(v)
+		}
+	}
+	page.Write(bits)
+
+	compressed, err := w.compress(page.Bytes())
+	if err != nil {
+		return columnChunk{}, err
+	}
+
+	var h encoder
+	h.begin()
+	h.fieldI32(1, 0) // DATA_PAGE
+	h.fieldI32(2, int32(page.Len()))
+	h.fieldI32(3, int32(len(compressed)))
+	h.fieldStruct(5, func() {
+		h.fieldI32(1, int32(len(w.rows)))
+		h.fieldI32(2, 0) // PLAIN
+		h.fieldI32(3, 3) // RLE
+		h.fieldI32(4, 3) // RLE
+	})
+	h.end()
+
+	chunk := columnChunk{
+		offset:       w.w.n,
+		numValues:    int64(len(w.rows)),
+		uncompressed: int64(h.buf.Len() + page.Len()),
+		compressed:   int64(h.buf.Len() + len(compressed)),
+	}
+	if _, err := w.w.Write(h.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	if _, err := w.w.Write(compressed); err != nil {
+		return columnChunk{}, err
+	}
+	return chunk, nil
+}
+
+func (w *Writer) compress(b []byte) ([]byte, error) {
+	switch w.codec {
+	case codecs["snappy"]:
+		return snappy.Encode(nil, b), nil
+	case codecs["gzip"]:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(b); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case codecs["zstd"]:
+		return w.zstd.EncodeAll(b, nil), nil
+	default:
+		return b, nil
+	}
+}
+
+func (w *Writer) fileMetaData() []byte {
+	var e encoder
+	e.begin()
+	e.fieldI32(1, 1)
+
+	e.fieldList(2, tStruct, len(w.columns)+1)
+	e.listStruct(func() {
+		e.fieldString(4, "schema")
+		e.fieldI32(5, int32(len(w.columns)))
+	})
+	for _, c := range w.columns {
+		e.listStruct(func() { schemaElement(&e, c) })
+	}
+
+	e.fieldI64(3, w.numRows)
+
+	e.fieldList(4, tStruct, len(w.rowGroups))
+	for _, rg := range w.rowGroups {
+		e.listStruct(func() {
+			e.fieldList(1, tStruct, len(rg.chunks))
+			for i, chunk := range rg.chunks {
+				c := w.columns[i]
+				e.listStruct(func() {
+					e.fieldI64(2, chunk.offset)
+					e.fieldStruct(3, func() {
+						e.fieldI32(1, int32(c.Type))
+						e.fieldList(2, tI32, 2)
+						e.i32(0) // PLAIN
+						e.i32(3) // RLE
+						e.fieldList(3, tBinary, 1)
+						e.listString(c.Name)
+						e.fieldI32(4, w.codec)
+						e.fieldI64(5, chunk.numValues)
+						e.fieldI64(6, chunk.uncompressed)
+						e.fieldI64(7, chunk.compressed)
+						e.fieldI64(9, chunk.offset)
+					})
+				})
+			}
+			e.fieldI64(2, rg.totalSize)
+			e.fieldI64(3, rg.numRows)
+		})
+	}
+
+	e.fieldString(6, createdBy)
+	e.end()
+	return e.Bytes()
+}
+
+// schemaElement writes a column with its converted type (for older readers) and logical type.
+func schemaElement(e *encoder, c Column) {
+	e.fieldI32(1, int32(c.Type))
+	if c.Type == FixedLenByteArray {
+		e.fieldI32(2, c.Length)
+	}
+	e.fieldI32(3, 1) // OPTIONAL
+	e.fieldString(4, c.Name)
+
+	switch c.Logical {
+	case String:
+		e.fieldI32(6, 0) // UTF8
+		e.fieldStruct(10, func() { e.fieldStruct(1, func() {}) })
+	case Decimal:
+		e.fieldI32(6, 5) // DECIMAL
+		e.fieldI32(7, c.Scale)
+		e.fieldI32(8, c.Precision)
+		e.fieldStruct(10, func() {
+			e.fieldStruct(5, func() {
+				e.fieldI32(1, c.Scale)
+				e.fieldI32(2, c.Precision)
+			})
+		})
+	case Timestamp:
+		e.fieldI32(6, 10) // TIMESTAMP_MICROS
+		e.fieldStruct(10, func() {
+			e.fieldStruct(8, func() {
+				e.fieldBool(1, true)
+				e.fieldStruct(2, func() { e.fieldStruct(2, func() {}) }) // MICROS
+			})
+		})
+	case JSON:
+		e.fieldI32(6, 19) // JSON
+		e.fieldStruct(10, func() { e.fieldStruct(12, func() {}) })
+	case UUID:
+		e.fieldStruct(10, func() { e.fieldStruct(14, func() {}) })
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,256 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/kream404/spoof/services/parquet"
+	"github.com/stretchr/testify/assert"
+)
+
+// decoder reads the thrift compact protocol into field id -> value maps.
+type decoder struct {
+	b   []byte
+	pos int
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b[d.pos:])
+	d.pos += n
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	u := d.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (d *decoder) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return d.varint()
+	case 8:
+		n := int(d.uvarint())
+		s := string(d.b[d.pos : d.pos+n])
+		d.pos += n
+		return s
+	case 9:
+		h := d.b[d.pos]
+		d.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(d.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = d.value(h & 0x0f)
+		}
+		return list
+	case 12:
+		return d.structure()
+	}
+	panic("unsupported thrift type")
+}
+
+func (d *decoder) structure() map[int]any {
+	out := map[int]any{}
+	last := 0
+	for {
+		h := d.b[d.pos]
+		d.pos++
+		if h == 0 {
+			return out
+		}
+		id := last + int(h>>4)
+		if h>>4 == 0 {
+			id = int(d.varint())
+		}
+		out[id] = d.value(h & 0x0f)
+		last = id
+	}
+}
+
+func footer(t *testing.T, file []byte) map[int]any {
+	assert.Equal(t, "PAR1", string(file[:4]))
+	assert.Equal(t, "PAR1", string(file[len(file)-4:]))
+	n := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	d := &decoder{b: file[len(file)-8-n : len(file)-8]}
+	return d.structure()
+}
+
+// readInt64Column decodes an optional int64 column chunk written with one data page.
+func readInt64Column(t *testing.T, file []byte, meta map[int]any, codec int64) []any {
+	d := &decoder{b: file, pos: int(meta[9].(int64))}
+	header := d.structure()
+	page := file[d.pos : d.pos+int(header[3].(int64))]
+	numValues := int(header[5].(map[int]any)[1].(int64))
+
+	switch codec {
+	case 1:
+		var err error
+		page, err = snappy.Decode(nil, page)
+		assert.NoError(t, err)
+	case 6:
+		dec, err := zstd.NewReader(nil)
+		assert.NoError(t, err)
+		page, err = dec.DecodeAll(page, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int(header[2].(int64)), len(page))
+
+	levelsLen := int(binary.LittleEndian.Uint32(page))
+	levels := &decoder{b: page[4 : 4+levelsLen]}
+	groups := int(levels.uvarint() >> 1)
+	bits := levels.b[levels.pos : levels.pos+groups]
+	values := page[4+levelsLen:]
+
+	out := make([]any, numValues)
+	for i := range out {
+		if bits[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		out[i] = int64(binary.LittleEndian.Uint64(values))
+		values = values[8:]
+	}
+	return out
+}
+
+func TestWriter(t *testing.T) {
+	columns := []parquet.Column{
+		{Name: "id", Type: parquet.Int64},
+		{Name: "amount", Type: parquet.Int64, Logical: parquet.Decimal, Scale: 2, Precision: 18},
+		{Name: "ratio", Type: parquet.Double},
+		{Name: "active", Type: parquet.Boolean},
+		{Name: "name", Type: parquet.ByteArray, Logical: parquet.String},
+		{Name: "ref", Type: parquet.FixedLenByteArray, Logical: parquet.UUID, Length: 16},
+	}
+
+	for _, tt := range []struct {
+		compression string
+		codec       int64
+	}{{"none", 0}, {"", 1}, {"zstd", 6}, {"gzip", 2}} {
+		t.Run(tt.compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := parquet.NewWriter(&buf, columns, parquet.Options{RowGroupSize: 2, Compression: tt.compression})
+			assert.NoError(t, err)
+
+			ref := make([]byte, 16)
+			assert.NoError(t, w.Write([]any{int64(1), int64(1250), 0.5, true, "a", ref}))
+			assert.NoError(t, w.Write([]any{nil, int64(-3), math.Pi, false, nil, ref}))
+			assert.NoError(t, w.Write([]any{int64(3), nil, nil, nil, "c", nil}))
+			assert.NoError(t, w.Close())
+
+			meta := footer(t, buf.Bytes())
+			assert.Equal(t, int64(3), meta[3])
+
+			schema := meta[2].([]any)
+			assert.Len(t, schema, len(columns)+1)
+			assert.Equal(t, int64(len(columns)), schema[0].(map[int]any)[5])
+			amount := schema[2].(map[int]any)
+			assert.Equal(t, "amount", amount[4])
+			assert.Equal(t, int64(5), amount[6]) // DECIMAL
+			assert.Equal(t, int64(2), amount[7])
+
+			groups := meta[4].([]any)
+			assert.Len(t, groups, 2)
+			assert.Equal(t, int64(2), groups[0].(map[int]any)[3])
+			assert.Equal(t, int64(1), groups[1].(map[int]any)[3])
+
+			if tt.codec == 2 {
+				return
+			}
+			var ids []any
+			for _, g := range groups {
+				chunk := g.(map[int]any)[1].([]any)[0].(map[int]any)[3].(map[int]any)
+				assert.Equal(t, tt.codec, chunk[4])
+				ids = append(ids, readInt64Column(t, buf.Bytes(), chunk, tt.codec)...)
+			}
+			assert.Equal(t, []any{int64(1), nil, int64(3)}, ids)
+		})
+	}
+}
+
+// TestWriter_PyArrow reads the writer's output with pyarrow, which shares none
+// of its code. It is skipped when python3 with pyarrow is not installed, unless
+// SPOOF_REQUIRE_PYARROW is set, as it is in CI.
+func TestWriter_PyArrow(t *testing.T) {
+	if err := exec.Command("python3", "-c", "import pyarrow").Run(); err != nil {
+		if os.Getenv("SPOOF_REQUIRE_PYARROW") != "" {
+			t.Fatalf("SPOOF_REQUIRE_PYARROW is set but python3 with pyarrow is not installed: %v", err)
+		}
+		t.Skip("python3 with pyarrow is not installed")
+	}
+
+	columns := []parquet.Column{
+		{Name: "id", Type: parquet.Int64},
+		{Name: "amount", Type: parquet.Int64, Logical: parquet.Decimal, Scale: 2, Precision: 18},
+		{Name: "ratio", Type: parquet.Double},
+		{Name: "active", Type: parquet.Boolean},
+		{Name: "name", Type: parquet.ByteArray, Logical: parquet.String},
+		{Name: "posted_at", Type: parquet.Int64, Logical: parquet.Timestamp},
+		{Name: "payload", Type: parquet.ByteArray, Logical: parquet.JSON},
+		{Name: "ref", Type: parquet.FixedLenByteArray, Logical: parquet.UUID, Length: 16},
+	}
+	ref := []byte{0x01, 0x8f, 0x3a, 0x10, 0x5b, 0x2c, 0x7d, 0x4e, 0x9a, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77}
+	posted := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC).UnixMicro()
+
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.parquet")
+			f, err := os.Create(path)
+			assert.NoError(t, err)
+			w, err := parquet.NewWriter(f, columns, parquet.Options{RowGroupSize: 2, Compression: compression})
+			assert.NoError(t, err)
+			assert.NoError(t, w.Write([]any{int64(1), int64(1250), 0.5, true, "a", posted, `{"k":1}`, ref}))
+			assert.NoError(t, w.Write([]any{nil, int64(-999999999999999999), math.Pi, false, nil, nil, nil, ref}))
+			assert.NoError(t, w.Write([]any{int64(3), nil, nil, nil, "c", posted, nil, nil}))
+			assert.NoError(t, w.Close())
+			assert.NoError(t, f.Close())
+
+			out, err := exec.Command("python3", "testdata/read_parquet.py", path).Output()
+			assert.NoError(t, err)
+
+			var got struct {
+				Schema map[string]string `json:"schema"`
+				Rows   []map[string]any  `json:"rows"`
+			}
+			assert.NoError(t, json.Unmarshal(out, &got))
+
+			assert.Equal(t, "int64", got.Schema["id"])
+			assert.Equal(t, "decimal128(18, 2)", got.Schema["amount"])
+			assert.Equal(t, "double", got.Schema["ratio"])
+			assert.Equal(t, "bool", got.Schema["active"])
+			assert.Equal(t, "string", got.Schema["name"])
+			assert.Equal(t, "timestamp[us, tz=UTC]", got.Schema["posted_at"])
+
+			assert.Equal(t, []map[string]any{
+				{"id": 1.0, "amount": "12.50", "ratio": 0.5, "active": true, "name": "a", "posted_at": "2024-03-01T12:30:00+00:00", "payload": `{"k":1}`, "ref": "018f3a105b2c7d4e9a11223344556677"},
+				{"id": nil, "amount": "-9999999999999999.99", "ratio": math.Pi, "active": false, "name": nil, "posted_at": nil, "payload": nil, "ref": "018f3a105b2c7d4e9a11223344556677"},
+				{"id": 3.0, "amount": nil, "ratio": nil, "active": nil, "name": "c", "posted_at": "2024-03-01T12:30:00+00:00", "payload": nil, "ref": nil},
+			}, got.Rows)
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := parquet.NewWriter(&bytes.Buffer{}, []parquet.Column{{Name: "id", Type: parquet.Int64}}, parquet.Options{Compression: "lzo"})
+	assert.Error(t, err)
+
+	w, err := parquet.NewWriter(&bytes.Buffer{}, []parquet.Column{{Name: "id", Type: parquet.Int64}}, parquet.Options{})
+	assert.NoError(t, err)
+	assert.Error(t, w.Write([]any{"1"}))
+	assert.Error(t, w.Write([]any{int64(1), int64(2)}))
+}
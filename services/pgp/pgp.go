@@ -0,0 +1,167 @@
+package pgp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+)
+
+const armorHeader = "-----BEGIN"
+
+// KeyRing is a set of public or private keys.
+type KeyRing = openpgp.EntityList
+
+// Key is a public key with its subkeys, and the private keys when they were read.
+type Key = openpgp.Entity
+
+// Result describes a decrypted message's signature.
+type Result struct {
+	Signed   bool
+	Verified bool   // signed by a key in the keyring, and the signature is good
+	KeyID    string // the signing key, when Signed
+}
+
+// ReadKeys reads the keys in an ASCII-armored or binary key file.
+func ReadKeys(path string) (KeyRing, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var keys KeyRing
+	if isArmored(r) {
+		keys, err = openpgp.ReadArmoredKeyRing(r)
+	} else {
+		keys, err = openpgp.ReadKeyRing(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", path, err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("read key %s: no keys found", path)
+	}
+	return keys, nil
+}
+
+// ReadPrivateKey reads the first private key in path and unlocks it with passphrase.
+func ReadPrivateKey(path, passphrase string) (*Key, error) {
+	keys, err := ReadKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range keys {
+		if e.PrivateKey == nil {
+			continue
+		}
+		if err := unlock(e, passphrase); err != nil {
+			return nil, fmt.Errorf("unlock key %s: %w", path, err)
+		}
+		return e, nil
+	}
+	return nil, fmt.Errorf("%s has no private key", path)
+}
+
+func unlock(e *Key, passphrase string) error {
+	if e.PrivateKey == nil || !e.PrivateKey.Encrypted && !anySubkeyEncrypted(e) {
+		return nil
+	}
+	if passphrase == "" {
+		return errors.New("key is protected by a passphrase")
+	}
+	return e.DecryptPrivateKeys([]byte(passphrase))
+}
+
+func anySubkeyEncrypted(e *Key) bool {
+	for _, s := range e.Subkeys {
+		if s.PrivateKey != nil && s.PrivateKey.Encrypted {
+			return true
+		}
+	}
+	return false
+}
+
+// Encrypt encrypts src to dst for every recipient, and signs it when signer is
+// not nil. name is the file name stored in the message.
+func Encrypt(dst io.Writer, src io.Reader, name string, recipients KeyRing, signer *Key, armored bool) error {
+	out := dst
+	var block io.WriteCloser
+	if armored {
+		var err error
+		if block, err = armor.Encode(dst, "PGP MESSAGE", nil); err != nil {
+			return err
+		}
+		out = block
+	}
+
+	plain, err := openpgp.Encrypt(out, recipients, signer, &openpgp.FileHints{IsBinary: true, FileName: filepath.Base(name)}, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(plain, src); err != nil {
+		return err
+	}
+	if err := plain.Close(); err != nil {
+		return err
+	}
+	if block != nil {
+		return block.Close()
+	}
+	return nil
+}
+
+// Decrypt decrypts an ASCII-armored or binary message from src to dst with the
+// private keys in keyring, unlocked with passphrase. A signature made by a key
+// in keyring is verified, and a bad signature is an error.
+func Decrypt(dst io.Writer, src io.Reader, keyring KeyRing, passphrase string) (Result, error) {
+	for _, e := range keyring {
+		if err := unlock(e, passphrase); err != nil {
+			return Result{}, err
+		}
+	}
+
+	r := bufio.NewReader(src)
+	var in io.Reader = r
+	if isArmored(r) {
+		block, err := armor.Decode(r)
+		if err != nil {
+			return Result{}, err
+		}
+		in = block.Body
+	}
+
+	md, err := openpgp.ReadMessage(in, keyring, nil, &packet.Config{})
+	if err != nil {
+		return Result{}, err
+	}
+	// the signature is checked once the whole body has been read
+	if _, err := io.Copy(dst, md.UnverifiedBody); err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Signed: md.IsSigned}
+	if md.IsSigned {
+		res.KeyID = fmt.Sprintf("%016X", md.SignedByKeyId)
+		if md.SignedBy != nil {
+			if md.SignatureError != nil {
+				return res, fmt.Errorf("bad signature from %s: %w", res.KeyID, md.SignatureError)
+			}
+			res.Verified = true
+		}
+	}
+	return res, nil
+}
+
+func isArmored(r *bufio.Reader) bool {
+	head, _ := r.Peek(64)
+	return bytes.HasPrefix(bytes.TrimLeft(head, " \t\r\n"), []byte(armorHeader))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/compress"
 )
 
 type S3Connector struct {
@@ -141,10 +142,17 @@ func (d *S3Connector) readObjectAsRows(ctx context.Context, bucket, key string)
 	}
 	defer obj.Body.Close()
 
-	ext := strings.ToLower(filepath.Ext(key))
+	// data.csv.gz is read as data.csv
+	body, err := compress.NewReader(obj.Body)
+	if err != nil {
+		return nil, fmt.Errorf("decompress s3://%s/%s: %w", bucket, key, err)
+	}
+	defer body.Close()
+
+	ext := strings.ToLower(filepath.Ext(compress.TrimExt(key)))
 	switch ext {
 	case ".json":
-		b, err := io.ReadAll(obj.Body)
+		b, err := io.ReadAll(body)
 		if err != nil {
 			return nil, err
 		}
@@ -154,7 +162,7 @@ func (d *S3Connector) readObjectAsRows(ctx context.Context, bucket, key string)
 		}
 		return normalizeJSON(outAny), nil
 	case ".jsonl", ".ndjson":
-		scanner := bufio.NewScanner(obj.Body)
+		scanner := bufio.NewScanner(body)
 		var rows []map[string]any
 		for scanner.Scan() {
 			line := strings.TrimSpace(scanner.Text())
@@ -172,7 +180,7 @@ func (d *S3Connector) readObjectAsRows(ctx context.Context, bucket, key string)
 		}
 		return rows, nil
 	case ".csv":
-		reader := csv.NewReader(obj.Body)
+		reader := csv.NewReader(body)
 		records, err := reader.ReadAll()
 		if err != nil {
 			return nil, err
@@ -195,7 +203,7 @@ func (d *S3Connector) readObjectAsRows(ctx context.Context, bucket, key string)
 		}
 		return rows, nil
 	default:
-		b, err := io.ReadAll(obj.Body)
+		b, err := io.ReadAll(body)
 		if err != nil {
 			return nil, err
 		}
@@ -247,10 +255,30 @@ func mimeFromExt(ext string) string {
 	switch strings.ToLower(ext) {
 	case ".json":
 		return "application/json"
+	case ".jsonl", ".ndjson":
+		return "application/x-ndjson"
 	case ".csv":
 		return "text/csv"
+	case ".parquet":
+		return "application/vnd.apache.parquet"
+	case ".avro":
+		return "application/avro"
+	case ".avsc":
+		return "application/json"
+	case ".sql":
+		return "application/sql"
+	case ".xml":
+		return "application/xml"
+	case ".xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 	case ".txt":
 		return "text/plain"
+	case ".gz":
+		return "application/gzip"
+	case ".zst":
+		return "application/zstd"
+	case ".bz2":
+		return "application/x-bzip2"
 	default:
 		return "application/octet-stream"
 	}
@@ -0,0 +1,355 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/kream404/spoof/models"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// maxSteps stops runaway Starlark scripts (e.g. an accidental infinite loop)
+	maxSteps = 10_000_000
+	// jsTimeout does the same for JavaScript, which has no step counter
+	jsTimeout = 5 * time.Second
+)
+
+// Env is what a script can see while generating one cell.
+type Env struct {
+	Index  int               // row index
+	Row    map[string]string // values generated earlier in this row
+	Parent map[string]string // values of the parent row, for nested json fields
+	Rng    *rand.Rand        // the file's seeded rng
+}
+
+type compiled struct {
+	name     string
+	starlark *starlark.Program
+	js       *goja.Program
+	vms      sync.Pool // *jsVM, so each goroutine reuses a runtime
+}
+
+// cache holds compiled scripts by language and source, or by path for script
+// files, so a file is read and compiled once.
+var cache sync.Map // language + "\x00" + source or path -> *compiled
+
+// Eval runs the field's script and returns the cell value. The script is
+// either inline or a path to a .star/.js file; the language comes from the
+// field's language, the file extension, or defaults to starlark.
+func Eval(field models.Field, env Env) (any, error) {
+	lang, path, err := resolve(field)
+	if err != nil {
+		return nil, err
+	}
+
+	key := lang + "\x00" + field.Script
+	c, ok := cache.Load(key)
+	if !ok {
+		src, name := field.Script, field.Name
+		if path {
+			raw, err := os.ReadFile(field.Script)
+			if err != nil {
+				return nil, fmt.Errorf("could not read script: %w", err)
+			}
+			src, name = string(raw), field.Script
+		}
+		p, err := compile(lang, name, src)
+		if err != nil {
+			return nil, err
+		}
+		c, _ = cache.LoadOrStore(key, p)
+	}
+
+	if lang == "javascript" {
+		return c.(*compiled).runJS(env)
+	}
+	return runStarlark(c.(*compiled).starlark, c.(*compiled).name, env)
+}
+
+// resolve returns the field's script language, and whether the script is a file path.
+func resolve(field models.Field) (lang string, path bool, err error) {
+	src := field.Script
+	if strings.TrimSpace(src) == "" {
+		return "", false, fmt.Errorf("script field %s has no script", field.Name)
+	}
+
+	ext := strings.ToLower(filepath.Ext(src))
+	path = !strings.ContainsAny(src, "\n(") && (ext == ".js" || ext == ".star")
+
+	switch strings.ToLower(field.Language) {
+	case "javascript", "js":
+		lang = "javascript"
+	case "starlark", "star":
+		lang = "starlark"
+	case "":
+		lang = "starlark"
+		if ext == ".js" {
+			lang = "javascript"
+		}
+	default:
+		return "", false, fmt.Errorf("unsupported script language %q (expected starlark or javascript)", field.Language)
+	}
+	return lang, path, nil
+}
+
+func compile(lang, name, src string) (*compiled, error) {
+	if lang == "javascript" {
+		// the runtime is reused between cells, so the script is evaluated in
+		// a fresh function scope: its declarations don't outlive the cell, and
+		// eval keeps its completion value as the script's value
+		if _, err := goja.Compile(name, src, false); err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+		quoted, err := json.Marshal(src)
+		if err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+		p, err := goja.Compile(name, "(function () { return eval("+string(quoted)+") })()", false)
+		if err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+		return &compiled{name: name, js: p}, nil
+	}
+
+	// a bare expression is its own value; programs assign to value
+	opts := syntax.LegacyFileOptions()
+	if _, err := opts.ParseExpr(name, src, 0); err == nil {
+		src = "value = (\n" + src + "\n)"
+	}
+	predeclared := map[string]bool{"row": true, "parent": true, "index": true, "rng": true}
+	_, p, err := starlark.SourceProgramOptions(opts, name, src, func(s string) bool { return predeclared[s] })
+	if err != nil {
+		return nil, fmt.Errorf("script: %w", err)
+	}
+	return &compiled{name: name, starlark: p}, nil
+}
+
+func float(rng *rand.Rand) float64 {
+	if rng != nil {
+		return rng.Float64()
+	}
+	return rand.Float64()
+}
+
+func intn(rng *rand.Rand, n int) int {
+	if rng != nil {
+		return rng.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+//
+// ───────────────────────── STARLARK ────────────────────────────
+//
+
+func runStarlark(p *starlark.Program, name string, env Env) (any, error) {
+	thread := &starlark.Thread{Name: name}
+	thread.SetMaxExecutionSteps(maxSteps)
+
+	globals, err := p.Init(thread, starlark.StringDict{
+		"row":    starlarkDict(env.Row),
+		"parent": starlarkDict(env.Parent),
+		"index":  starlark.MakeInt(env.Index),
+		"rng":    starlarkRng(env.Rng),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("script: %w", err)
+	}
+
+	v, ok := globals["value"]
+	if !ok {
+		return nil, fmt.Errorf("script: must be an expression or assign to value")
+	}
+	return fromStarlark(v)
+}
+
+func starlarkDict(m map[string]string) *starlark.Dict {
+	d := starlark.NewDict(len(m))
+	for k, v := range m {
+		_ = d.SetKey(starlark.String(k), starlark.String(v))
+	}
+	d.Freeze()
+	return d
+}
+
+func starlarkRng(rng *rand.Rand) *starlarkstruct.Module {
+	return &starlarkstruct.Module{
+		Name: "rng",
+		Members: starlark.StringDict{
+			"random": starlark.NewBuiltin("random", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+					return nil, err
+				}
+				return starlark.Float(float(rng)), nil
+			}),
+			"randint": starlark.NewBuiltin("randint", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var lo, hi int
+				if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &lo, &hi); err != nil {
+					return nil, err
+				}
+				if hi < lo {
+					return nil, fmt.Errorf("randint: %d > %d", lo, hi)
+				}
+				return starlark.MakeInt(lo + intn(rng, hi-lo+1)), nil
+			}),
+			"choice": starlark.NewBuiltin("choice", func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+				var seq starlark.Indexable
+				if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &seq); err != nil {
+					return nil, err
+				}
+				if seq.Len() == 0 {
+					return nil, fmt.Errorf("choice: empty sequence")
+				}
+				return seq.Index(intn(rng, seq.Len())), nil
+			}),
+		},
+	}
+}
+
+func fromStarlark(v starlark.Value) (any, error) {
+	switch x := v.(type) {
+	case *starlark.List, starlark.Tuple, *starlark.Dict:
+		// collections are written as JSON, like JavaScript arrays and objects
+		g, err := toGo(v)
+		if err != nil {
+			return nil, err
+		}
+		out, err := json.Marshal(g)
+		if err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+		return string(out), nil
+	case starlark.Int:
+		if i, ok := x.Int64(); ok {
+			return i, nil
+		}
+		return x.String(), nil
+	default:
+		return toGo(v)
+	}
+}
+
+func toGo(v starlark.Value) (any, error) {
+	switch x := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return string(x), nil
+	case starlark.Bool:
+		return bool(x), nil
+	case starlark.Int:
+		if i, ok := x.Int64(); ok {
+			return i, nil
+		}
+		return json.Number(x.String()), nil
+	case starlark.Float:
+		return float64(x), nil
+	case *starlark.List, starlark.Tuple:
+		seq := x.(starlark.Indexable)
+		out := make([]any, seq.Len())
+		for i := range out {
+			g, err := toGo(seq.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = g
+		}
+		return out, nil
+	case *starlark.Dict:
+		out := make(map[string]any, x.Len())
+		for _, item := range x.Items() {
+			k := item[0].String()
+			if s, ok := item[0].(starlark.String); ok {
+				k = string(s)
+			}
+			g, err := toGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			out[k] = g
+		}
+		return out, nil
+	default:
+		return v.String(), nil
+	}
+}
+
+//
+// ───────────────────────── JAVASCRIPT ──────────────────────────
+//
+
+// jsVM is a runtime with the script globals installed. env is the cell being
+// generated, which the globals read on every run.
+type jsVM struct {
+	vm  *goja.Runtime
+	env Env
+}
+
+func newJSVM() *jsVM {
+	j := &jsVM{vm: goja.New()}
+	j.vm.SetRandSource(func() float64 { return float(j.env.Rng) })
+
+	rng := j.vm.NewObject()
+	_ = rng.Set("random", func() float64 { return float(j.env.Rng) })
+	_ = rng.Set("randint", func(lo, hi int) (int, error) {
+		if hi < lo {
+			return 0, fmt.Errorf("randint: %d > %d", lo, hi)
+		}
+		return lo + intn(j.env.Rng, hi-lo+1), nil
+	})
+	_ = rng.Set("choice", func(items []any) (any, error) {
+		if len(items) == 0 {
+			return nil, fmt.Errorf("choice: empty array")
+		}
+		return items[intn(j.env.Rng, len(items))], nil
+	})
+	_ = j.vm.Set("rng", rng)
+	return j
+}
+
+func (c *compiled) runJS(env Env) (any, error) {
+	j, _ := c.vms.Get().(*jsVM)
+	if j == nil {
+		j = newJSVM()
+	}
+	defer c.vms.Put(j)
+
+	vm := j.vm
+	j.env = env
+	_ = vm.Set("row", env.Row)
+	_ = vm.Set("parent", env.Parent)
+	_ = vm.Set("index", env.Index)
+
+	timer := time.AfterFunc(jsTimeout, func() { vm.Interrupt("timed out") })
+	defer vm.ClearInterrupt()
+	defer timer.Stop()
+
+	v, err := vm.RunProgram(c.js)
+	if err != nil {
+		return nil, fmt.Errorf("script: %w", err)
+	}
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return nil, nil
+	}
+
+	switch x := v.Export().(type) {
+	case map[string]any, []any:
+		out, err := json.Marshal(x)
+		if err != nil {
+			return nil, fmt.Errorf("script: %w", err)
+		}
+		return string(out), nil
+	default:
+		return x, nil
+	}
+}
@@ -0,0 +1,103 @@
+package script_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kream404/spoof/models"
+	"github.com/kream404/spoof/services/script"
+	"github.com/stretchr/testify/assert"
+)
+
+func env(seed int64) script.Env {
+	return script.Env{
+		Index:  7,
+		Row:    map[string]string{"first": "Ada", "amount": "12.50"},
+		Parent: map[string]string{"account": "ACC-1"},
+		Rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+func TestEval_Starlark(t *testing.T) {
+	// a bare expression is the value
+	v, err := script.Eval(models.Field{Name: "ref", Script: `parent["account"] + "-" + str(index)`}, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "ACC-1-7", v)
+
+	// programs assign to value
+	v, err = script.Eval(models.Field{Name: "total", Script: "amount = float(row[\"amount\"])\nvalue = amount * 2"}, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, v)
+
+	// collections are JSON
+	v, err = script.Eval(models.Field{Name: "tags", Script: `{"id": index, "tags": ["a", row["first"]], "big": 1 << 70}`}, env(1))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":7,"tags":["a","Ada"],"big":1180591620717411303424}`, v.(string))
+
+	_, err = script.Eval(models.Field{Name: "bad", Script: "x = 1"}, env(1))
+	assert.ErrorContains(t, err, "must be an expression or assign to value")
+
+	_, err = script.Eval(models.Field{Name: "loop", Script: "def f():\n  for i in range(1000000000):\n    pass\nf()\nvalue = 1"}, env(1))
+	assert.ErrorContains(t, err, "too many steps")
+}
+
+func TestEval_JavaScript(t *testing.T) {
+	f := models.Field{Name: "greeting", Language: "javascript", Script: "row.first.toUpperCase() + ':' + (index + 1)"}
+	v, err := script.Eval(f, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "ADA:8", v)
+
+	f = models.Field{Name: "obj", Language: "js", Script: "({id: index, tags: ['a']})"}
+	v, err = script.Eval(f, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":7,"tags":["a"]}`, v)
+
+	// the runtime is reused, so declarations must not leak between cells
+	f = models.Field{Name: "decl", Language: "javascript", Script: "let n = index * 2; const m = n + 1; m"}
+	for i := 0; i < 3; i++ {
+		v, err = script.Eval(f, env(1))
+		assert.NoError(t, err)
+		assert.EqualValues(t, 15, v)
+	}
+
+	// a var or function set by one row is gone by the next
+	f = models.Field{Name: "vars", Language: "javascript", Script: "var first = typeof seen === 'undefined'; var seen = 1; function g() { return first } g() ? 'first' : 'leaked'"}
+	for i := 0; i < 2; i++ {
+		v, err = script.Eval(f, env(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "first", v)
+	}
+
+	_, err = script.Eval(models.Field{Name: "bad", Language: "lua", Script: "1"}, env(1))
+	assert.ErrorContains(t, err, `unsupported script language "lua"`)
+}
+
+func TestEval_SeededRng(t *testing.T) {
+	for _, f := range []models.Field{
+		{Name: "s", Script: `[rng.randint(1, 100) for _ in range(5)] + [rng.choice(["a", "b", "c"])]`},
+		{Name: "j", Language: "javascript", Script: `[rng.randint(1, 100), Math.random(), rng.choice(["a", "b", "c"])].join(",")`},
+	} {
+		a, err := script.Eval(f, env(42))
+		assert.NoError(t, err)
+		b, err := script.Eval(f, env(42))
+		assert.NoError(t, err)
+		assert.Equal(t, a, b, f.Name)
+	}
+}
+
+func TestEval_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ref.js")
+	assert.NoError(t, os.WriteFile(path, []byte("'REF-' + index"), 0o644))
+
+	v, err := script.Eval(models.Field{Name: "ref", Script: path}, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "REF-7", v)
+
+	// the file is only read for the first cell
+	assert.NoError(t, os.Remove(path))
+	v, err = script.Eval(models.Field{Name: "ref", Script: path}, env(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "REF-7", v)
+}